@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/coderhyme/arch-lint/internal/loader"
+)
+
+func runCache(args []string) {
+	if len(args) != 1 || args[0] != "clean" {
+		log.Fatalf("usage: arch-lint cache clean")
+	}
+
+	dir, err := loader.DefaultCacheDir()
+	if err != nil {
+		log.Fatalf("Failed to locate cache directory: %v", err)
+	}
+
+	cache, err := loader.OpenCache(dir)
+	if err != nil {
+		log.Fatalf("Failed to open cache: %v", err)
+	}
+	if err := cache.Clean(); err != nil {
+		log.Fatalf("Failed to clean cache: %v", err)
+	}
+
+	fmt.Printf("Removed import cache at %s\n", dir)
+}
+
+// openCache opens the default import cache. The cache only speeds up loading, so it is
+// skipped with a warning when it cannot be opened.
+func openCache() *loader.Cache {
+	dir, err := loader.DefaultCacheDir()
+	if err != nil {
+		log.Printf("Warning: import cache disabled: %v", err)
+		return nil
+	}
+
+	cache, err := loader.OpenCache(dir)
+	if err != nil {
+		log.Printf("Warning: import cache disabled: %v", err)
+		return nil
+	}
+	return cache
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"github.com/coderhyme/arch-lint/internal/config"
+	"github.com/coderhyme/arch-lint/internal/vcs"
+)
+
+// changedPackages returns the imports of the packages whose .go files changed since rev.
+// When a config file changed, every package is returned since any rule may have changed.
+func changedPackages(ctx context.Context, ws *workspace, rev string) (map[string]map[string]struct{}, error) {
+	files, err := vcs.ChangedFiles(ctx, ws.root, rev)
+	if err != nil {
+		return nil, err
+	}
+
+	configFiles := ws.configFiles()
+	changed := make(map[string]map[string]struct{})
+	for _, file := range files {
+		file = filepath.FromSlash(file)
+		if filepath.Base(file) == config.DefaultFileName || configFiles[file] {
+			return ws.packages, nil
+		}
+
+		if !strings.HasSuffix(file, ".go") || strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		dir := filepath.Dir(file)
+		if imports, exists := ws.packages[dir]; exists {
+			changed[dir] = imports
+		}
+	}
+
+	return changed, nil
+}
+
+// configFiles returns the config files the workspace was loaded from, relative to its root.
+func (ws *workspace) configFiles() map[string]bool {
+	files := make(map[string]bool)
+	add := func(path string) {
+		if path == "" {
+			return
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(ws.root, path)
+		}
+		if rel, err := filepath.Rel(ws.root, path); err == nil {
+			files[rel] = true
+		}
+	}
+
+	add(ws.configPath)
+	for _, grp := range ws.cfg.Groups {
+		add(grp.Source)
+	}
+	return files
+}
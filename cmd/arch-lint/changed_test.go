@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/coderhyme/arch-lint/internal/config"
+)
+
+type ChangedSuite struct {
+	suite.Suite
+	ws *workspace
+}
+
+func TestChangedSuite(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	suite.Run(t, new(ChangedSuite))
+}
+
+func (s *ChangedSuite) SetupTest() {
+	root := s.T().TempDir()
+	s.ws = &workspace{
+		root:       root,
+		configPath: filepath.Join(root, config.DefaultFileName),
+		cfg: &config.Config{
+			Version: 1,
+			Groups: map[string]*config.Group{
+				"domain": {Source: filepath.Join("rules", "domain.yaml")},
+			},
+		},
+		packages: map[string]map[string]struct{}{
+			filepath.Join("internal", "a"): {"fmt": {}},
+			filepath.Join("internal", "b"): {"os": {}},
+		},
+	}
+
+	s.git("init", "-q")
+	s.git("config", "user.email", "test@example.com")
+	s.git("config", "user.name", "test")
+	s.git("config", "commit.gpgsign", "false")
+	s.write(config.DefaultFileName, "version: 1\n")
+	s.write("rules/domain.yaml", "groups: {}\n")
+	s.write("internal/a/a.go", "package a\n")
+	s.write("internal/b/b.go", "package b\n")
+	s.git("add", "-A")
+	s.git("commit", "-q", "-m", "initial")
+}
+
+func (s *ChangedSuite) git(args ...string) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = s.ws.root
+	out, err := cmd.CombinedOutput()
+	s.Require().NoError(err, string(out))
+}
+
+func (s *ChangedSuite) write(name, content string) {
+	path := filepath.Join(s.ws.root, name)
+	s.Require().NoError(os.MkdirAll(filepath.Dir(path), 0o755))
+	s.Require().NoError(os.WriteFile(path, []byte(content), 0o644))
+}
+
+func (s *ChangedSuite) TestChangedPackages_OnlyPackagesWithChangedSources() {
+	// given
+	s.write("internal/a/a.go", "package a\n\nimport \"fmt\"\n")
+	s.write("internal/b/b_test.go", "package b\n")
+	s.write("internal/c/c.go", "package c\n")
+	s.write("README.md", "docs\n")
+
+	// when
+	changed, err := changedPackages(context.Background(), s.ws, "HEAD")
+
+	// then - tests, non-Go files and packages outside the workspace are ignored
+	s.Require().NoError(err)
+	assert.Equal(s.T(), map[string]map[string]struct{}{
+		filepath.Join("internal", "a"): {"fmt": {}},
+	}, changed)
+}
+
+func (s *ChangedSuite) TestChangedPackages_UntrackedFile() {
+	// given
+	s.write("internal/b/new.go", "package b\n")
+
+	// when
+	changed, err := changedPackages(context.Background(), s.ws, "HEAD")
+
+	// then
+	s.Require().NoError(err)
+	assert.Equal(s.T(), []string{filepath.Join("internal", "b")}, keys(changed))
+}
+
+func (s *ChangedSuite) TestChangedPackages_ConfigChangedChecksEverything() {
+	// given
+	s.write(config.DefaultFileName, "version: 1\ngroups: {}\n")
+
+	// when
+	changed, err := changedPackages(context.Background(), s.ws, "HEAD")
+
+	// then
+	s.Require().NoError(err)
+	assert.Equal(s.T(), s.ws.packages, changed)
+}
+
+func (s *ChangedSuite) TestChangedPackages_IncludedConfigChangedChecksEverything() {
+	// given
+	s.write("rules/domain.yaml", "groups:\n  domain: {}\n")
+
+	// when
+	changed, err := changedPackages(context.Background(), s.ws, "HEAD")
+
+	// then
+	s.Require().NoError(err)
+	assert.Equal(s.T(), s.ws.packages, changed)
+}
+
+func (s *ChangedSuite) TestChangedPackages_NoChanges() {
+	// when
+	changed, err := changedPackages(context.Background(), s.ws, "HEAD")
+
+	// then
+	s.Require().NoError(err)
+	assert.Empty(s.T(), changed)
+}
+
+func keys(m map[string]map[string]struct{}) []string {
+	var result []string
+	for key := range m {
+		result = append(result, key)
+	}
+	return result
+}
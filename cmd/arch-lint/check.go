@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/coderhyme/arch-lint/internal/checker"
+	"github.com/coderhyme/arch-lint/internal/config"
+	"github.com/coderhyme/arch-lint/internal/fix"
+	"github.com/coderhyme/arch-lint/internal/report"
+)
+
+func runCheck(args []string) {
+	fs := flag.NewFlagSet("arch-lint", flag.ExitOnError)
+	opts := registerWorkspaceFlags(fs)
+	var format, changedSince, failOn string
+	var fixAliases bool
+	fs.StringVar(&format, "format", "text", "output format: text, dsm or dsm-csv")
+	fs.StringVar(&changedSince, "changed-since", "", "only check packages with .go files changed since this git revision")
+	fs.StringVar(&failOn, "fail-on", string(config.SeverityError), "lowest violation severity that fails the run: info, warning or error")
+	fs.BoolVar(&fixAliases, "fix", false, "rewrite imports to the names required by aliases")
+	_ = fs.Parse(args)
+	opts.parseTarget(fs.Args())
+
+	threshold, err := config.ParseSeverity(failOn)
+	if err != nil {
+		log.Fatalf("Invalid --fail-on: %v", err)
+	}
+
+	ctx := context.Background()
+	ws := loadWorkspace(ctx, opts)
+
+	packages := ws.packages
+	if changedSince != "" {
+		packages, err = changedPackages(ctx, ws, changedSince)
+		if err != nil {
+			log.Fatalf("Failed to determine changed packages: %v", err)
+		}
+	}
+
+	result, err := ws.check(ctx, packages)
+	if err != nil {
+		log.Fatalf("Failed to check dependencies: %v", err)
+	}
+
+	if fixAliases {
+		applyAliasFixes(ctx, ws, result)
+	}
+
+	switch format {
+	case "text":
+		printViolations(result)
+		printExceptions(result)
+	case "dsm", "dsm-csv":
+		printDSM(ctx, os.Stdout, ws, packages, result, format == "dsm-csv")
+	default:
+		log.Fatalf("Unknown format %q", format)
+	}
+
+	if result.Fails(threshold) {
+		os.Exit(1)
+	}
+}
+
+// applyAliasFixes rewrites the imports of alias violations that require a name and removes
+// the violations it fixed from result.
+func applyAliasFixes(ctx context.Context, ws *workspace, result *checker.Result) {
+	fixed, errs := fix.Aliases(ws.root, result.Violations, ws.packageNamer(ctx).Resolve)
+	for _, e := range errs {
+		log.Printf("Warning: %v", e)
+	}
+	if len(fixed) == 0 {
+		return
+	}
+
+	done := make(map[checker.Violation]bool, len(fixed))
+	files := make(map[string]bool)
+	for _, v := range fixed {
+		done[v] = true
+		files[v.Pos.Filename] = true
+	}
+	remaining := result.Violations[:0]
+	for _, v := range result.Violations {
+		if !done[v] {
+			remaining = append(remaining, v)
+		}
+	}
+	result.Violations = remaining
+
+	// Logged rather than printed, so machine-readable output stays intact.
+	log.Printf("Fixed %d import alias(es) in %d file(s)", len(fixed), len(files))
+}
+
+func printViolations(result *checker.Result) {
+	if len(result.Violations) == 0 {
+		fmt.Printf("No violations found (%d packages checked)\n", result.PackagesCount)
+		return
+	}
+
+	counts := make(map[config.Severity]int)
+	for _, v := range result.Violations {
+		counts[v.Severity]++
+	}
+
+	fmt.Printf("Found %d violation(s) (%d error, %d warning, %d info):\n\n", len(result.Violations),
+		counts[config.SeverityError], counts[config.SeverityWarning], counts[config.SeverityInfo])
+	for _, v := range result.Violations {
+		source := ""
+		if v.Source != "" {
+			source = " (" + v.Source + ")"
+		}
+		fmt.Printf("  [%s] %s\n    %s\n    denied by %s%s: %s\n", v.Severity, v.Package, describeTarget(v), v.DeniedBy(), source, v.Rule)
+		if explanation := v.Explanation(); explanation != "" {
+			fmt.Printf("    %s\n", explanation)
+		}
+		fmt.Println()
+	}
+}
+
+// describeTarget describes what a violating package did: import a package, import it in a
+// forbidden form or by the wrong name, use one of its identifiers, call a function, expose a
+// type in its API or declare a //go:linkname directive at a position, reach a package
+// through a chain of imports, or count more packages than a limit allows.
+func describeTarget(v checker.Violation) string {
+	switch v.Kind {
+	case checker.KindLimit:
+		return fmt.Sprintf("counts %d packages, top offenders: %s", v.Count, v.Offenders)
+	case checker.KindTransitive:
+		return fmt.Sprintf("reaches %s via %s", v.Import, v.Chain)
+	case checker.KindExport:
+		return fmt.Sprintf("exposes %s in %s at %s", v.Target(), v.Decl, v.Pos)
+	case checker.KindLinkname:
+		return fmt.Sprintf("has %s at %s", v.Directive, v.Pos)
+	case checker.KindImportForm:
+		return fmt.Sprintf("imports %s (%s import) at %s", v.Import, v.Form, v.Pos)
+	case checker.KindAlias:
+		return fmt.Sprintf("imports %s as %s at %s", v.Import, v.Alias, v.Pos)
+	case checker.KindCall:
+		return fmt.Sprintf("calls %s at %s", v.Call, v.Pos)
+	case checker.KindSymbol, checker.KindUnsafe:
+		return fmt.Sprintf("uses %s at %s", v.Target(), v.Pos)
+	default:
+		return "imports " + v.Import
+	}
+}
+
+func printExceptions(result *checker.Result) {
+	if len(result.Suppressed) > 0 {
+		fmt.Printf("%d violation(s) suppressed by exceptions\n\n", len(result.Suppressed))
+	}
+	if len(result.ExpiredExceptions) > 0 {
+		fmt.Printf("Expired exceptions (%d):\n", len(result.ExpiredExceptions))
+		for _, e := range result.ExpiredExceptions {
+			printException(e)
+		}
+		fmt.Println()
+	}
+	if len(result.UnusedExceptions) > 0 {
+		fmt.Printf("Unused exceptions (%d):\n", len(result.UnusedExceptions))
+		for _, e := range result.UnusedExceptions {
+			printException(e)
+		}
+		fmt.Println()
+	}
+}
+
+func printException(e config.Exception) {
+	fmt.Printf("  %s -> %s (expires %s", e.Package, e.Import, e.Expires)
+	if e.Owner != "" {
+		fmt.Printf(", owner %s", e.Owner)
+	}
+	if e.Source != "" {
+		fmt.Printf(", %s", e.Source)
+	}
+	fmt.Println(")")
+	if e.Reason != "" {
+		fmt.Printf("    %s\n", e.Reason)
+	}
+}
+
+// printDSM writes the dependency matrix of the checked packages, so edges of packages left
+// out by --changed-since are not shown as allowed.
+func printDSM(ctx context.Context, w io.Writer, ws *workspace, packages map[string]map[string]struct{}, result *checker.Result, asCSV bool) {
+	model, err := report.Build(ctx, ws.modulePath, packages, result, ws.manager)
+	if err != nil {
+		log.Fatalf("Failed to build report: %v", err)
+	}
+
+	dsm, err := report.NewDSM(ctx, model, ws.manager)
+	if err != nil {
+		log.Fatalf("Failed to build dependency matrix: %v", err)
+	}
+
+	if asCSV {
+		err = report.WriteDSMCSV(w, dsm)
+	} else {
+		err = report.WriteDSMText(w, dsm)
+	}
+	if err != nil {
+		log.Fatalf("Failed to write dependency matrix: %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/coderhyme/arch-lint/internal/checker"
+	"github.com/coderhyme/arch-lint/internal/config"
+)
+
+type CheckSuite struct {
+	suite.Suite
+}
+
+func TestCheckSuite(t *testing.T) {
+	suite.Run(t, new(CheckSuite))
+}
+
+// module writes a module with the given files to a temporary directory and returns its root.
+func (s *CheckSuite) module(files map[string]string) string {
+	root := s.T().TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		s.Require().NoError(os.MkdirAll(filepath.Dir(path), 0o755))
+		s.Require().NoError(os.WriteFile(path, []byte(content), 0o644))
+	}
+	return root
+}
+
+func (s *CheckSuite) TestDescribeTarget_EachKind() {
+	// given
+	pos := token.Position{Filename: "internal/domain/user/user.go", Line: 7, Column: 2}
+	violations := []checker.Violation{
+		{Kind: checker.KindImport, Import: "internal/infra"},
+		{Kind: checker.KindPrivate, Import: "internal/billing/invoice"},
+		{Kind: checker.KindTransitive, Import: "database/sql", Chain: "internal/infra -> database/sql"},
+		{Kind: checker.KindSymbol, Import: "internal/legacy", Symbol: "DB", Pos: pos},
+		{Kind: checker.KindCall, Call: "os.Exit", Pos: pos},
+		{Kind: checker.KindExport, Import: "internal/store", Symbol: "Row", Decl: "Handler", Pos: pos},
+		{Kind: checker.KindImportForm, Import: "internal/shared", Form: "dot", Pos: pos},
+		{Kind: checker.KindAlias, Import: "k8s.io/api/core/v1", Alias: "v1", Pos: pos},
+		{Kind: checker.KindLinkname, Directive: "//go:linkname nanotime runtime.nanotime", Pos: pos},
+		{Kind: checker.KindUnsafe, Import: "unsafe", Symbol: "Pointer", Pos: pos},
+		{Kind: checker.KindLimit, Count: 12, Offenders: "internal/domain/user (7)"},
+	}
+
+	// when
+	var descriptions []string
+	for _, v := range violations {
+		descriptions = append(descriptions, describeTarget(v))
+	}
+
+	// then
+	assert.Equal(s.T(), []string{
+		"imports internal/infra",
+		"imports internal/billing/invoice",
+		"reaches database/sql via internal/infra -> database/sql",
+		"uses internal/legacy.DB at internal/domain/user/user.go:7:2",
+		"calls os.Exit at internal/domain/user/user.go:7:2",
+		"exposes internal/store.Row in Handler at internal/domain/user/user.go:7:2",
+		"imports internal/shared (dot import) at internal/domain/user/user.go:7:2",
+		"imports k8s.io/api/core/v1 as v1 at internal/domain/user/user.go:7:2",
+		"has //go:linkname nanotime runtime.nanotime at internal/domain/user/user.go:7:2",
+		"uses unsafe.Pointer at internal/domain/user/user.go:7:2",
+		"counts 12 packages, top offenders: internal/domain/user (7)",
+	}, descriptions)
+}
+
+func (s *CheckSuite) TestCheck_AliasesUseResolvedPackageNames() {
+	// given
+	root := s.module(map[string]string{
+		"go.mod":                           "module github.com/example/app\n\ngo 1.22\n\nrequire k8s.io/api v0.0.0\n\nreplace k8s.io/api => ./third_party/api\n",
+		"third_party/api/go.mod":           "module k8s.io/api\n\ngo 1.22\n",
+		"third_party/api/core/v1/types.go": "package v1\n\ntype Pod struct{}\n",
+		"internal/api/user.go":             "package api\n\nimport \"k8s.io/api/core/v1\"\n\nvar pod v1.Pod\n",
+		config.DefaultFileName:             "version: 1\naliases:\n  k8s.io/api/core/v1: corev1\n",
+	})
+	ws, err := loadWorkspaceAt(context.Background(), root, filepath.Join(root, config.DefaultFileName), &workspaceOptions{jobs: 1, noCache: true})
+	s.Require().NoError(err)
+
+	// when
+	result, err := ws.check(context.Background(), ws.packages)
+
+	// then
+	s.Require().NoError(err)
+	s.Require().Len(result.Violations, 1)
+	assert.Equal(s.T(), "imports k8s.io/api/core/v1 as v1 at internal/api/user.go:3", describeTarget(result.Violations[0]))
+	assert.Equal(s.T(), "corev1", result.Violations[0].WantAlias)
+}
+
+func (s *CheckSuite) TestPrintDSM_OnlyCheckedPackages() {
+	// given
+	root := s.module(map[string]string{
+		"go.mod":                          "module github.com/example/app\n\ngo 1.22\n",
+		"internal/api/old/old.go":         "package old\n\nimport _ \"github.com/example/app/internal/repository/sql\"\n",
+		"internal/api/changed/changed.go": "package changed\n",
+		"internal/repository/sql/sql.go":  "package sql\n",
+		config.DefaultFileName: "version: 1\ngroups:\n  api:\n    paths: [internal/api/**]\n    dependencies:\n" +
+			"      deny:\n        groups: [repository]\n  repository:\n    paths: [internal/repository/**]\n",
+	})
+	ws, err := loadWorkspaceAt(context.Background(), root, filepath.Join(root, config.DefaultFileName), &workspaceOptions{jobs: 1, noCache: true})
+	s.Require().NoError(err)
+	packages := map[string]map[string]struct{}{
+		filepath.Join("internal", "api", "changed"): ws.packages[filepath.Join("internal", "api", "changed")],
+	}
+	result, err := ws.check(context.Background(), packages)
+	s.Require().NoError(err)
+
+	// when
+	var out strings.Builder
+	printDSM(context.Background(), &out, ws, packages, result, true)
+
+	// then - the unchecked violating edge of internal/api/old is not shown as allowed
+	assert.Equal(s.T(), "group,api,repository,notes\n"+
+		"api,0:unknown,0:unknown,\n"+
+		"repository,0:unknown,0:unknown,\n", out.String())
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/coderhyme/arch-lint/internal/config"
+	"github.com/coderhyme/arch-lint/internal/report"
+	"github.com/coderhyme/arch-lint/internal/vcs"
+)
+
+func runDiff(args []string) {
+	fs := flag.NewFlagSet("arch-lint diff", flag.ExitOnError)
+	opts := registerWorkspaceFlags(fs)
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		log.Fatalf("usage: arch-lint diff [flags] <base> <head> (git revisions or directories)")
+	}
+
+	ctx := context.Background()
+	base, err := loadModel(ctx, fs.Arg(0), opts)
+	if err != nil {
+		log.Fatalf("Failed to load %s: %v", fs.Arg(0), err)
+	}
+	head, err := loadModel(ctx, fs.Arg(1), opts)
+	if err != nil {
+		log.Fatalf("Failed to load %s: %v", fs.Arg(1), err)
+	}
+
+	diff := report.Compare(base, head)
+	if err := report.WriteDiffText(os.Stdout, diff); err != nil {
+		log.Fatalf("Failed to write diff: %v", err)
+	}
+
+	for _, v := range diff.NewViolations {
+		if v.Severity.AtLeast(config.SeverityError) {
+			os.Exit(1)
+		}
+	}
+}
+
+// loadModel loads the tree named by ref, which is either a directory or a git revision of
+// the repository in the working directory. It returns errors instead of exiting so that an
+// exported revision is always removed.
+func loadModel(ctx context.Context, ref string, opts *workspaceOptions) (*report.Model, error) {
+	root := ref
+	if info, err := os.Stat(ref); err != nil || !info.IsDir() {
+		if root, err = exportRevision(ctx, ref); err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(root)
+	} else if root, err = filepath.Abs(ref); err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", ref, err)
+	}
+
+	ws, err := loadWorkspaceAt(ctx, root, treeConfigPath(root, opts), opts)
+	if err != nil {
+		return nil, err
+	}
+	result, err := ws.check(ctx, ws.packages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check dependencies: %w", err)
+	}
+
+	model, err := report.Build(ctx, ws.modulePath, ws.packages, result, ws.manager)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build model: %w", err)
+	}
+	return model, nil
+}
+
+func exportRevision(ctx context.Context, rev string) (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	dir, err := os.MkdirTemp("", "arch-lint-diff-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	if err := vcs.Export(ctx, cwd, rev, dir); err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("failed to export revision %s: %w", rev, err)
+	}
+	return dir, nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/coderhyme/arch-lint/internal/loader"
+	"github.com/coderhyme/arch-lint/internal/metrics"
+)
+
+func runMetrics(args []string) {
+	fs := flag.NewFlagSet("arch-lint metrics", flag.ExitOnError)
+	opts := registerWorkspaceFlags(fs)
+	var format string
+	fs.StringVar(&format, "format", "table", "output format: table or json")
+	_ = fs.Parse(args)
+	opts.parseTarget(fs.Args())
+
+	ctx := context.Background()
+	ws := loadWorkspace(ctx, opts)
+
+	dirs := make([]string, 0, len(ws.graph))
+	for pkg := range ws.graph {
+		dirs = append(dirs, pkg)
+	}
+	types, errs := loader.CountTypes(ctx, ws.root, dirs, loader.Options{Jobs: ws.jobs})
+	for _, e := range errs {
+		log.Printf("Warning: %v", e)
+	}
+
+	rep, err := metrics.Compute(ctx, ws.cfg, ws.modulePath, ws.graph, types, ws.manager)
+	if err != nil {
+		log.Fatalf("Failed to compute metrics: %v", err)
+	}
+
+	// Metrics are computed over the whole module; package patterns select the rows shown.
+	selected := rep.Packages[:0]
+	for _, pm := range rep.Packages {
+		if _, ok := ws.packages[pm.Package]; ok {
+			selected = append(selected, pm)
+		}
+	}
+	rep.Packages = selected
+
+	switch format {
+	case "table":
+		printMetrics(rep)
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(rep); err != nil {
+			log.Fatalf("Failed to write metrics: %v", err)
+		}
+	default:
+		log.Fatalf("Unknown format %q", format)
+	}
+
+	if len(rep.Breaches) > 0 {
+		os.Exit(1)
+	}
+}
+
+func printMetrics(rep *metrics.Report) {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PACKAGE\tCa\tCe\tI\tA\tD")
+	for _, pm := range rep.Packages {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.2f\t%.2f\t%.2f\n", pm.Package, pm.Ca, pm.Ce, pm.Instability, pm.Abstractness, pm.Distance)
+	}
+	_ = tw.Flush()
+
+	if len(rep.Groups) > 0 {
+		fmt.Println()
+		fmt.Fprintln(tw, "GROUP\tPACKAGES\tCa\tCe\tI\tA\tD")
+		for _, gm := range rep.Groups {
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%.2f\t%.2f\t%.2f\n", gm.Group, gm.Packages, gm.Ca, gm.Ce, gm.Instability, gm.Abstractness, gm.Distance)
+		}
+		_ = tw.Flush()
+	}
+
+	if len(rep.Breaches) > 0 {
+		fmt.Printf("\nThresholds exceeded (%d):\n", len(rep.Breaches))
+		for _, b := range rep.Breaches {
+			fmt.Printf("  group %q: %s %.2f (threshold %.2f)\n", b.Group, b.Metric, b.Value, b.Threshold)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/coderhyme/arch-lint/internal/report"
+)
+
+func runReport(args []string) {
+	fs := flag.NewFlagSet("arch-lint report", flag.ExitOnError)
+	opts := registerWorkspaceFlags(fs)
+	var htmlPath string
+	fs.StringVar(&htmlPath, "html", "", "write a self-contained HTML report to this file")
+	_ = fs.Parse(args)
+	opts.parseTarget(fs.Args())
+
+	if htmlPath == "" {
+		log.Fatalf("report requires --html <file>")
+	}
+
+	ctx := context.Background()
+	ws := loadWorkspace(ctx, opts)
+
+	result, err := ws.check(ctx, ws.packages)
+	if err != nil {
+		log.Fatalf("Failed to check dependencies: %v", err)
+	}
+
+	model, err := report.Build(ctx, ws.modulePath, ws.packages, result, ws.manager)
+	if err != nil {
+		log.Fatalf("Failed to build report: %v", err)
+	}
+
+	if err := writeReport(htmlPath, model); err != nil {
+		log.Fatalf("Failed to write report: %v", err)
+	}
+
+	fmt.Printf("Report written to %s (%d violation(s))\n", htmlPath, len(result.Violations))
+}
+
+// writeReport writes the HTML report of model to path. The file is closed before returning,
+// so errors flushing it are reported too.
+func writeReport(path string, model *report.Model) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := report.WriteHTML(f, model); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,365 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"go/token"
+	"io/fs"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/coderhyme/arch-lint/internal/checker"
+	"github.com/coderhyme/arch-lint/internal/config"
+	"github.com/coderhyme/arch-lint/internal/loader"
+)
+
+// settleDelay is how long watch waits for further events before re-checking, so a save
+// that touches several files is handled as one change.
+const settleDelay = 200 * time.Millisecond
+
+func runWatch(args []string) {
+	fs := flag.NewFlagSet("arch-lint watch", flag.ExitOnError)
+	opts := registerWorkspaceFlags(fs)
+	var poll time.Duration
+	fs.DurationVar(&poll, "poll", 0, "poll for changes at this interval instead of using file system notifications")
+	_ = fs.Parse(args)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	opts.parseTarget(fs.Args())
+	t := resolveTarget(opts)
+	root := t.root
+
+	s := newWatchSession(ctx, t, opts)
+	s.check(ctx)
+
+	changes := make(chan []string)
+	watchErr := make(chan error, 1)
+	go func() {
+		defer close(changes)
+		if poll > 0 {
+			watchErr <- pollChanges(ctx, root, poll, changes)
+		} else {
+			watchErr <- notifyChanges(ctx, root, changes)
+		}
+	}()
+
+	fmt.Println("Watching for changes (press Ctrl+C to stop)")
+	for paths := range changes {
+		s.apply(ctx, paths)
+	}
+	if err := <-watchErr; err != nil && ctx.Err() == nil {
+		stop()
+		log.Fatalf("Failed to watch for changes: %v", err)
+	}
+}
+
+type watchSession struct {
+	ws     *workspace
+	target *target
+	opts   *workspaceOptions
+	index  *loader.Index
+	// violations is nil until the first check.
+	violations map[violationKey]checker.Violation
+}
+
+// violationKey identifies a violation across checks. The position is left out, so edits
+// that only move a symbol use are not reported; occurrence numbers the violations that are
+// otherwise equal, so repeated calls or uses in one package are each counted.
+type violationKey struct {
+	violation  checker.Violation
+	occurrence int
+}
+
+func newWatchSession(ctx context.Context, t *target, opts *workspaceOptions) *watchSession {
+	cfg, manager, err := loadConfig(t.root, t.configPath, opts)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	index, errs := loader.NewIndex(ctx, t.root, loaderOptions(opts))
+	for _, e := range errs {
+		log.Printf("Warning: %v", e)
+	}
+	if index == nil {
+		log.Fatalf("Failed to determine module path")
+	}
+
+	return &watchSession{
+		ws: &workspace{
+			root:       t.root,
+			configPath: t.configPath,
+			cfg:        cfg,
+			modulePath: index.ModulePath(),
+			packages:   t.filter(index.Packages()),
+			graph:      index.Packages(),
+			files:      index.Files(),
+			manager:    manager,
+			jobs:       opts.jobs,
+		},
+		target: t,
+		opts:   opts,
+		index:  index,
+	}
+}
+
+// apply updates the session for changed paths and reports how the violations changed.
+func (s *watchSession) apply(ctx context.Context, paths []string) {
+	configFiles := s.ws.configFiles()
+	reloadConfig := false
+	packagesChanged := false
+
+	for _, path := range paths {
+		rel, err := filepath.Rel(s.ws.root, path)
+		if err != nil {
+			continue
+		}
+		if configFiles[rel] || filepath.Base(rel) == config.DefaultFileName {
+			reloadConfig = true
+			continue
+		}
+
+		tracked, err := s.index.Update(path)
+		if err != nil {
+			log.Printf("Warning: %v", err)
+		}
+		packagesChanged = packagesChanged || tracked
+	}
+
+	if reloadConfig {
+		cfg, manager, err := loadConfig(s.ws.root, s.ws.configPath, s.opts)
+		if err != nil {
+			log.Printf("Warning: keeping previous config: %v", err)
+		} else {
+			s.ws.cfg, s.ws.manager = cfg, manager
+			fmt.Println("Config reloaded")
+		}
+	}
+	if !reloadConfig && !packagesChanged {
+		return
+	}
+
+	s.ws.graph = s.index.Packages()
+	s.ws.files = s.index.Files()
+	s.ws.packages = s.target.filter(s.ws.graph)
+	s.check(ctx)
+}
+
+// check runs the checker and prints the violations that appeared or disappeared since the
+// previous check.
+func (s *watchSession) check(ctx context.Context) {
+	result, err := s.ws.check(ctx, s.ws.packages)
+	if err != nil {
+		log.Printf("Warning: failed to check dependencies: %v", err)
+		return
+	}
+
+	current := keyViolations(result.Violations)
+
+	var added, resolved []checker.Violation
+	for key, v := range current {
+		if _, exists := s.violations[key]; !exists {
+			added = append(added, v)
+		}
+	}
+	for key, v := range s.violations {
+		if _, exists := current[key]; !exists {
+			resolved = append(resolved, v)
+		}
+	}
+	initial := s.violations == nil
+	s.violations = current
+	if !initial && len(added) == 0 && len(resolved) == 0 {
+		return
+	}
+
+	sortViolations(added)
+	sortViolations(resolved)
+	fmt.Printf("[%s] %d violation(s), %d new, %d resolved\n", time.Now().Format("15:04:05"), len(current), len(added), len(resolved))
+	for _, v := range added {
+		fmt.Printf("  + %s %s (%s, %s: %s)\n", v.Package, describeTarget(v), v.Severity, v.DeniedBy(), v.Rule)
+		if explanation := v.Explanation(); explanation != "" {
+			fmt.Printf("      %s\n", explanation)
+		}
+	}
+	for _, v := range resolved {
+		fmt.Printf("  - %s %s (%s, %s: %s)\n", v.Package, describeTarget(v), v.Severity, v.DeniedBy(), v.Rule)
+	}
+}
+
+func keyViolations(violations []checker.Violation) map[violationKey]checker.Violation {
+	keyed := make(map[violationKey]checker.Violation, len(violations))
+	occurrences := make(map[checker.Violation]int)
+	for _, v := range violations {
+		key := v
+		key.Pos = token.Position{}
+		keyed[violationKey{violation: key, occurrence: occurrences[key]}] = v
+		occurrences[key]++
+	}
+	return keyed
+}
+
+func sortViolations(vs []checker.Violation) {
+	sort.Slice(vs, func(i, j int) bool {
+		if vs[i].Package != vs[j].Package {
+			return vs[i].Package < vs[j].Package
+		}
+		if vs[i].Import != vs[j].Import {
+			return vs[i].Import < vs[j].Import
+		}
+		return vs[i].GroupName < vs[j].GroupName
+	})
+}
+
+func watchedDir(root, path string, d fs.DirEntry) bool {
+	return path == root || !(strings.HasPrefix(d.Name(), ".") || d.Name() == "vendor")
+}
+
+func watchedFile(path string) bool {
+	return strings.HasSuffix(path, ".go") || strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml")
+}
+
+// notifyChanges sends batches of changed files reported by file system notifications.
+func notifyChanges(ctx context.Context, root string, changes chan<- []string) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	pending := make(map[string]bool)
+	timer := time.NewTimer(settleDelay)
+	timer.Stop()
+
+	// addTree watches dir and its subdirectories. For a directory created while watching,
+	// queue also marks the files already in it as changed, since they may have been written
+	// before the directory was watched.
+	addTree := func(dir string, queue bool) error {
+		return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if !d.IsDir() {
+				if queue && watchedFile(path) {
+					pending[path] = true
+					timer.Reset(settleDelay)
+				}
+				return nil
+			}
+			if !watchedDir(root, path, d) {
+				return filepath.SkipDir
+			}
+			return watcher.Add(path)
+		})
+	}
+	if err := addTree(root, false); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Printf("Warning: %v", err)
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err := addTree(event.Name, true); err != nil {
+						log.Printf("Warning: %v", err)
+					}
+					continue
+				}
+			}
+			if watchedFile(event.Name) {
+				pending[event.Name] = true
+				timer.Reset(settleDelay)
+			}
+		case <-timer.C:
+			changes <- sortedPaths(pending)
+			pending = make(map[string]bool)
+		}
+	}
+}
+
+// pollChanges sends batches of files whose size or modification time changed between scans.
+func pollChanges(ctx context.Context, root string, interval time.Duration, changes chan<- []string) error {
+	type stamp struct {
+		size    int64
+		modTime time.Time
+	}
+	scan := func() map[string]stamp {
+		stamps := make(map[string]stamp)
+		_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if d.IsDir() {
+				if !watchedDir(root, path, d) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !watchedFile(path) {
+				return nil
+			}
+			if info, err := d.Info(); err == nil {
+				stamps[path] = stamp{size: info.Size(), modTime: info.ModTime()}
+			}
+			return nil
+		})
+		return stamps
+	}
+
+	previous := scan()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			current := scan()
+			changed := make(map[string]bool)
+			for path, st := range current {
+				if prev, exists := previous[path]; !exists || prev != st {
+					changed[path] = true
+				}
+			}
+			for path := range previous {
+				if _, exists := current[path]; !exists {
+					changed[path] = true
+				}
+			}
+			previous = current
+			if len(changed) > 0 {
+				changes <- sortedPaths(changed)
+			}
+		}
+	}
+}
+
+func sortedPaths(set map[string]bool) []string {
+	paths := make([]string, 0, len(set))
+	for path := range set {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
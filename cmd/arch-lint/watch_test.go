@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/coderhyme/arch-lint/internal/checker"
+)
+
+type WatchSuite struct {
+	suite.Suite
+}
+
+func TestWatchSuite(t *testing.T) {
+	suite.Run(t, new(WatchSuite))
+}
+
+func (s *WatchSuite) TestNotifyChanges_QueuesFilesOfMovedInDirectory() {
+	// given
+	root := s.T().TempDir()
+	outside := filepath.Join(s.T().TempDir(), "pkg")
+	s.Require().NoError(os.MkdirAll(filepath.Join(outside, "sub"), 0o755))
+	s.Require().NoError(os.WriteFile(filepath.Join(outside, "a.go"), []byte("package pkg\n"), 0o644))
+	s.Require().NoError(os.WriteFile(filepath.Join(outside, "sub", "b.go"), []byte("package sub\n"), 0o644))
+	s.Require().NoError(os.WriteFile(filepath.Join(outside, "notes.txt"), []byte("notes\n"), 0o644))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	changes := make(chan []string)
+	done := make(chan error, 1)
+	go func() { done <- notifyChanges(ctx, root, changes) }()
+	time.Sleep(100 * time.Millisecond)
+
+	// when
+	s.Require().NoError(os.Rename(outside, filepath.Join(root, "pkg")))
+
+	// then
+	select {
+	case paths := <-changes:
+		assert.Equal(s.T(), []string{
+			filepath.Join(root, "pkg", "a.go"),
+			filepath.Join(root, "pkg", "sub", "b.go"),
+		}, paths)
+	case <-ctx.Done():
+		s.Fail("no changes reported")
+	}
+	cancel()
+	assert.ErrorIs(s.T(), <-done, context.Canceled)
+}
+
+func (s *WatchSuite) TestKeyViolations_KeepsRepeatedViolationsApart() {
+	// given
+	call := checker.Violation{Package: "internal/a", GroupName: "domain", Call: "os.Exit", Rule: "forbidden call"}
+	first, second := call, call
+	first.Pos = token.Position{Filename: "a.go", Line: 3}
+	second.Pos = token.Position{Filename: "a.go", Line: 9}
+	moved := call
+	moved.Pos = token.Position{Filename: "a.go", Line: 4}
+
+	// when
+	before := keyViolations([]checker.Violation{first, second})
+	after := keyViolations([]checker.Violation{moved, second})
+
+	// then - both calls are counted, and moving one is not a change
+	assert.Len(s.T(), before, 2)
+	for key := range before {
+		_, exists := after[key]
+		assert.True(s.T(), exists)
+	}
+}
@@ -0,0 +1,396 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/coderhyme/arch-lint/internal/analysis"
+	"github.com/coderhyme/arch-lint/internal/checker"
+	"github.com/coderhyme/arch-lint/internal/config"
+	"github.com/coderhyme/arch-lint/internal/groups"
+	"github.com/coderhyme/arch-lint/internal/loader"
+)
+
+type workspace struct {
+	root       string
+	configPath string
+	cfg        *config.Config
+	modulePath string
+	packages   map[string]map[string]struct{}
+	// graph holds the imports of every package of the module, including packages that
+	// package patterns exclude.
+	graph map[string]map[string]struct{}
+	// files holds the import declarations of every file, keyed by its path relative to root.
+	files   map[string][]loader.Import
+	manager groups.GroupManager
+	// jobs bounds the number of files parsed concurrently.
+	jobs int
+}
+
+type workspaceOptions struct {
+	configPath string
+	nested     bool
+	jobs       int
+	noCache    bool
+	// root and patterns come from positional arguments, see parseTarget.
+	root     string
+	patterns []string
+}
+
+func registerWorkspaceFlags(fs *flag.FlagSet) *workspaceOptions {
+	opts := &workspaceOptions{}
+	fs.StringVar(&opts.configPath, "config", "", "path to config file, relative to the module root (default: nearest "+config.DefaultFileName+" in the current or a parent directory)")
+	fs.BoolVar(&opts.nested, "nested", true, "also load .arch-lint.yaml files found in subdirectories")
+	fs.IntVar(&opts.jobs, "jobs", runtime.NumCPU(), "number of files to parse concurrently")
+	fs.BoolVar(&opts.noCache, "no-cache", false, "parse every file instead of using the import cache")
+	return opts
+}
+
+// parseTarget reads the optional module root and package patterns from positional
+// arguments. The first argument is the module root when it is a directory containing go.mod.
+func (opts *workspaceOptions) parseTarget(args []string) {
+	if len(args) > 0 && isModuleRoot(args[0]) {
+		opts.root = args[0]
+		args = args[1:]
+	}
+	opts.patterns = args
+}
+
+func isModuleRoot(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, "go.mod"))
+	return err == nil && !info.IsDir()
+}
+
+// target is the module, config and packages selected on the command line.
+type target struct {
+	root       string
+	configPath string
+	// patterns are package patterns relative to root.
+	patterns []string
+}
+
+// resolveTarget applies the defaults for the module root and config. Without an explicit
+// root, the module containing the working directory is used and package patterns are relative
+// to the working directory; with one, patterns are relative to the root. Without --config, the
+// nearest .arch-lint.yaml in the starting directory or its parents is used.
+func resolveTarget(opts *workspaceOptions) *target {
+	cwd, err := os.Getwd()
+	if err != nil {
+		log.Fatalf("Failed to get working directory: %v", err)
+	}
+
+	t := &target{}
+	start := cwd
+	if opts.root != "" {
+		if t.root, err = filepath.Abs(opts.root); err != nil {
+			log.Fatalf("Failed to resolve %s: %v", opts.root, err)
+		}
+		start = t.root
+	} else if t.root, err = findModuleRoot(cwd); err != nil {
+		log.Fatalf("Failed to find module root: %v", err)
+	}
+
+	switch {
+	case opts.configPath == "":
+		if t.configPath, err = findConfig(start, t.root, opts.nested); err != nil {
+			log.Fatalf("Failed to find config: %v", err)
+		}
+	case filepath.IsAbs(opts.configPath):
+		t.configPath = opts.configPath
+	default:
+		t.configPath = filepath.Join(t.root, opts.configPath)
+	}
+
+	if t.patterns, err = relativePatterns(t.root, start, opts.patterns); err != nil {
+		log.Fatalf("Invalid package pattern: %v", err)
+	}
+
+	return t
+}
+
+// filter restricts packages to the target's package patterns.
+func (t *target) filter(packages map[string]map[string]struct{}) map[string]map[string]struct{} {
+	if len(t.patterns) == 0 {
+		return packages
+	}
+
+	filtered, err := loader.FilterPackages(packages, t.patterns)
+	if err != nil {
+		log.Fatalf("Invalid package pattern: %v", err)
+	}
+	return filtered
+}
+
+// loadWorkspace loads the module selected by opts, restricted to its package patterns.
+func loadWorkspace(ctx context.Context, opts *workspaceOptions) *workspace {
+	t := resolveTarget(opts)
+	ws, err := loadWorkspaceAt(ctx, t.root, t.configPath, opts)
+	if err != nil {
+		log.Fatalf("Failed to load workspace: %v", err)
+	}
+	ws.packages = t.filter(ws.packages)
+	return ws
+}
+
+// loadWorkspaceAt loads the module rooted at root with the config at configPath.
+func loadWorkspaceAt(ctx context.Context, root, configPath string, opts *workspaceOptions) (*workspace, error) {
+	cfg, manager, err := loadConfig(root, configPath, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	index, errs := loader.NewIndex(ctx, root, loaderOptions(opts))
+	for _, e := range errs {
+		log.Printf("Warning: %v", e)
+	}
+	if index == nil {
+		return nil, errors.New("failed to determine module path")
+	}
+
+	packages := index.Packages()
+	return &workspace{
+		root:       root,
+		configPath: configPath,
+		cfg:        cfg,
+		modulePath: index.ModulePath(),
+		packages:   packages,
+		graph:      packages,
+		files:      index.Files(),
+		manager:    manager,
+		jobs:       opts.jobs,
+	}, nil
+}
+
+// check checks packages against the workspace's groups and suppresses the violations
+// covered by the config's exceptions. Files are parsed again for symbol and unsafe uses,
+// packages type-checked for calls and exported APIs, and the package graph walked for
+// transitive rules and limits, only when the config has rules that need them.
+func (ws *workspace) check(ctx context.Context, packages map[string]map[string]struct{}) (*checker.Result, error) {
+	result, err := checker.Check(ctx, ws.modulePath, packages, ws.manager)
+	if err != nil {
+		return nil, err
+	}
+
+	dirs := make([]string, 0, len(packages))
+	for pkg := range packages {
+		dirs = append(dirs, pkg)
+	}
+
+	if ws.cfg.HasPrivateGroups() {
+		violations, err := checker.CheckPrivate(ctx, ws.modulePath, packages, ws.manager)
+		if err != nil {
+			return nil, err
+		}
+		result.Violations = append(result.Violations, violations...)
+	}
+
+	if len(ws.cfg.Aliases) > 0 {
+		violations, err := checker.CheckAliases(ws.modulePath, ws.files, packages, ws.cfg.Aliases, ws.packageNamer(ctx).Name)
+		if err != nil {
+			return nil, err
+		}
+		result.Violations = append(result.Violations, violations...)
+	}
+
+	if ws.cfg.HasUnsafeRules() {
+		uses, errs := loader.LoadUnsafeUses(ctx, ws.root, dirs, loader.Options{Jobs: ws.jobs})
+		for _, e := range errs {
+			log.Printf("Warning: %v", e)
+		}
+
+		violations, err := checker.CheckUnsafe(ctx, uses, ws.manager)
+		if err != nil {
+			return nil, err
+		}
+		result.Violations = append(result.Violations, violations...)
+	}
+
+	if ws.cfg.HasImportFormRules() {
+		violations, err := checker.CheckImportForms(ctx, ws.modulePath, ws.files, packages, ws.manager)
+		if err != nil {
+			return nil, err
+		}
+		result.Violations = append(result.Violations, violations...)
+	}
+
+	if ws.cfg.HasSymbolRules() {
+		uses, errs := loader.LoadSymbolUses(ctx, ws.root, ws.modulePath, dirs, loader.Options{Jobs: ws.jobs})
+		for _, e := range errs {
+			log.Printf("Warning: %v", e)
+		}
+
+		violations, err := checker.CheckSymbols(ctx, ws.modulePath, uses, ws.manager)
+		if err != nil {
+			return nil, err
+		}
+		result.Violations = append(result.Violations, violations...)
+	}
+
+	if ws.cfg.HasCallRules() || ws.cfg.HasExportRules() {
+		prog, errs := analysis.Load(ctx, ws.root, ws.modulePath, dirs)
+		for _, e := range errs {
+			log.Printf("Warning: %v", e)
+		}
+		if prog == nil {
+			return nil, fmt.Errorf("failed to type-check packages")
+		}
+
+		if ws.cfg.HasCallRules() {
+			violations, err := checker.CheckCalls(ctx, prog.Calls(), ws.manager)
+			if err != nil {
+				return nil, err
+			}
+			result.Violations = append(result.Violations, violations...)
+		}
+		if ws.cfg.HasExportRules() {
+			violations, err := checker.CheckExports(ctx, prog.ExportedTypeRefs(), ws.manager)
+			if err != nil {
+				return nil, err
+			}
+			result.Violations = append(result.Violations, violations...)
+		}
+	}
+
+	if ws.cfg.HasTransitiveRules() {
+		violations, err := checker.CheckTransitive(ctx, ws.modulePath, packages, ws.graph, ws.manager)
+		if err != nil {
+			return nil, err
+		}
+		result.Violations = append(result.Violations, violations...)
+	}
+
+	if ws.cfg.HasLimits() {
+		violations, err := checker.CheckLimits(ctx, ws.modulePath, packages, ws.graph, ws.manager)
+		if err != nil {
+			return nil, err
+		}
+		result.Violations = append(result.Violations, violations...)
+	}
+
+	result.ApplyExceptions(ws.cfg.Exceptions, packages, time.Now())
+	return result, nil
+}
+
+// packageNamer resolves the package names of the imports of the workspace's files.
+func (ws *workspace) packageNamer(ctx context.Context) *loader.PackageNamer {
+	seen := make(map[string]bool)
+	var importPaths []string
+	for _, imports := range ws.files {
+		for _, imp := range imports {
+			if !seen[imp.Path] {
+				seen[imp.Path] = true
+				importPaths = append(importPaths, imp.Path)
+			}
+		}
+	}
+	sort.Strings(importPaths)
+
+	namer := loader.NewPackageNamer(ws.root, ws.modulePath)
+	if err := namer.Load(ctx, importPaths); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	return namer
+}
+
+// treeConfigPath returns the config of the tree rooted at root without searching parent
+// directories.
+func treeConfigPath(root string, opts *workspaceOptions) string {
+	if opts.configPath == "" {
+		return filepath.Join(root, config.DefaultFileName)
+	}
+	if filepath.IsAbs(opts.configPath) {
+		return opts.configPath
+	}
+	return filepath.Join(root, opts.configPath)
+}
+
+func findModuleRoot(dir string) (string, error) {
+	for d := dir; ; d = filepath.Dir(d) {
+		if isModuleRoot(d) {
+			return d, nil
+		}
+		if filepath.Dir(d) == d {
+			return "", fmt.Errorf("no go.mod found in %s or any parent directory", dir)
+		}
+	}
+}
+
+// findConfig returns the nearest config in dir or its parents. When nested configs are
+// loaded, configs below the module root are nested configs rather than candidates.
+func findConfig(dir, root string, nested bool) (string, error) {
+	for d := dir; ; d = filepath.Dir(d) {
+		if nested && d != root && isWithin(root, d) {
+			continue
+		}
+
+		path := filepath.Join(d, config.DefaultFileName)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+		if filepath.Dir(d) == d {
+			return "", fmt.Errorf("no %s found in %s or any parent directory", config.DefaultFileName, dir)
+		}
+	}
+}
+
+func isWithin(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// relativePatterns converts package patterns relative to base into patterns relative to root.
+func relativePatterns(root, base string, patterns []string) ([]string, error) {
+	var result []string
+	for _, pattern := range patterns {
+		path := pattern
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(base, pattern)
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, filepath.ToSlash(rel))
+	}
+	return result, nil
+}
+
+func loadConfig(root, configPath string, opts *workspaceOptions) (*config.Config, groups.GroupManager, error) {
+	var cfg *config.Config
+	var err error
+	if opts.nested {
+		cfg, err = config.LoadTree(configPath, root)
+	} else {
+		cfg, err = config.Load(configPath)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	manager, err := groups.NewGroupManager(cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build group manager: %w", err)
+	}
+
+	return cfg, manager, nil
+}
+
+func loaderOptions(opts *workspaceOptions) loader.Options {
+	loadOpts := loader.Options{Jobs: opts.jobs}
+	if !opts.noCache {
+		loadOpts.Cache = openCache()
+	}
+	return loadOpts
+}
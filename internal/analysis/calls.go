@@ -0,0 +1,59 @@
+package analysis
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// Call is a reference to a function, method or builtin: a call, or a function or method
+// value that may be called later.
+type Call struct {
+	// Package is the calling package, relative to the module root.
+	Package string
+	// Name identifies the callee like types.Func.FullName, such as "os.Exit" or
+	// "(*log.Logger).Fatal", with module packages relative to the module root. Builtins are
+	// named plainly, such as "panic".
+	Name string
+	Pos  token.Position
+}
+
+// Calls returns the references to functions, methods and called builtins in the program.
+// They are resolved with type information, so renamed imports and method values on
+// variables are attributed to the function they refer to.
+func (p *Program) Calls() []Call {
+	var calls []Call
+	for _, pkg := range p.packages {
+		relPkg := p.relPackage(pkg.PkgPath)
+		for _, file := range pkg.Syntax {
+			ast.Inspect(file, func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.CallExpr:
+					if ident, ok := ast.Unparen(n.Fun).(*ast.Ident); ok {
+						if builtin, ok := pkg.TypesInfo.Uses[ident].(*types.Builtin); ok {
+							calls = append(calls, Call{Package: relPkg, Name: builtin.Name(), Pos: p.position(ident.Pos())})
+						}
+					}
+				case *ast.Ident:
+					if fn, ok := pkg.TypesInfo.Uses[n].(*types.Func); ok {
+						calls = append(calls, Call{Package: relPkg, Name: p.funcName(fn), Pos: p.position(n.Pos())})
+					}
+				}
+				return true
+			})
+		}
+	}
+	return calls
+}
+
+func (p *Program) funcName(fn *types.Func) string {
+	fn = fn.Origin()
+	sig := fn.Type().(*types.Signature)
+	if recv := sig.Recv(); recv != nil {
+		return "(" + types.TypeString(recv.Type(), p.qualifier) + ")." + fn.Name()
+	}
+	if fn.Pkg() == nil {
+		return fn.Name()
+	}
+	return p.relPackage(fn.Pkg().Path()) + "." + fn.Name()
+}
@@ -0,0 +1,169 @@
+package analysis
+
+import (
+	"go/token"
+	"go/types"
+)
+
+// TypeRef is a reference from the exported API of a package to a named type of another
+// package.
+type TypeRef struct {
+	// Package is the package declaring the API, relative to the module root.
+	Package string
+	// Decl is the exported declaration containing the reference, such as "NewService",
+	// "User.DB" for a struct field or "Store.Open" for a method.
+	Decl string
+	// TypePackage is the package of the referenced type, relative to the module root for
+	// module packages.
+	TypePackage string
+	TypeName    string
+	Pos         token.Position
+}
+
+// ExportedTypeRefs returns the named types of other packages referenced by exported
+// declarations: function signatures, variable and constant types, exported struct fields
+// including embedded types, exported interface methods, methods of exported types and the
+// types exported types are defined as. Aliases are followed to the type they denote.
+func (p *Program) ExportedTypeRefs() []TypeRef {
+	var refs []TypeRef
+	for _, pkg := range p.packages {
+		c := &refCollector{prog: p, pkg: pkg.Types, seen: make(map[refKey]bool)}
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			obj := scope.Lookup(name)
+			if !obj.Exported() {
+				continue
+			}
+
+			switch obj := obj.(type) {
+			case *types.TypeName:
+				c.typeName(obj)
+			default:
+				c.collect(obj.Name(), obj.Type(), obj.Pos())
+			}
+		}
+		refs = append(refs, c.refs...)
+	}
+	return refs
+}
+
+type refKey struct {
+	decl string
+	typ  *types.TypeName
+}
+
+type refCollector struct {
+	prog *Program
+	pkg  *types.Package
+	refs []TypeRef
+	// seen holds the types already reported for a declaration.
+	seen map[refKey]bool
+}
+
+func (c *refCollector) typeName(obj *types.TypeName) {
+	if obj.IsAlias() {
+		c.collect(obj.Name(), obj.Type(), obj.Pos())
+		return
+	}
+
+	named, ok := obj.Type().(*types.Named)
+	if !ok {
+		return
+	}
+
+	switch u := named.Underlying().(type) {
+	case *types.Struct:
+		for i := 0; i < u.NumFields(); i++ {
+			if field := u.Field(i); field.Exported() {
+				c.collect(obj.Name()+"."+field.Name(), field.Type(), field.Pos())
+			}
+		}
+	case *types.Interface:
+		for i := 0; i < u.NumExplicitMethods(); i++ {
+			if m := u.ExplicitMethod(i); m.Exported() {
+				c.collect(obj.Name()+"."+m.Name(), m.Type(), m.Pos())
+			}
+		}
+		for i := 0; i < u.NumEmbeddeds(); i++ {
+			c.collect(obj.Name(), u.EmbeddedType(i), obj.Pos())
+		}
+	default:
+		c.collect(obj.Name(), u, obj.Pos())
+	}
+
+	for i := 0; i < named.NumMethods(); i++ {
+		if m := named.Method(i); m.Exported() {
+			c.collect(obj.Name()+"."+m.Name(), m.Type(), m.Pos())
+		}
+	}
+}
+
+// collect records the named types of other packages that t is built from. Named types are
+// not expanded, since their own API is checked with their package.
+func (c *refCollector) collect(decl string, t types.Type, pos token.Pos) {
+	switch t := t.(type) {
+	case *types.Alias:
+		c.collect(decl, types.Unalias(t), pos)
+	case *types.Named:
+		c.add(decl, t.Obj(), pos)
+		for i := 0; i < t.TypeArgs().Len(); i++ {
+			c.collect(decl, t.TypeArgs().At(i), pos)
+		}
+	case *types.Pointer:
+		c.collect(decl, t.Elem(), pos)
+	case *types.Slice:
+		c.collect(decl, t.Elem(), pos)
+	case *types.Array:
+		c.collect(decl, t.Elem(), pos)
+	case *types.Chan:
+		c.collect(decl, t.Elem(), pos)
+	case *types.Map:
+		c.collect(decl, t.Key(), pos)
+		c.collect(decl, t.Elem(), pos)
+	case *types.Signature:
+		for _, tuple := range []*types.Tuple{t.Params(), t.Results()} {
+			for i := 0; i < tuple.Len(); i++ {
+				v := tuple.At(i)
+				vpos := v.Pos()
+				if !vpos.IsValid() {
+					vpos = pos
+				}
+				c.collect(decl, v.Type(), vpos)
+			}
+		}
+	case *types.Struct:
+		for i := 0; i < t.NumFields(); i++ {
+			if field := t.Field(i); field.Exported() {
+				c.collect(decl, field.Type(), field.Pos())
+			}
+		}
+	case *types.Interface:
+		for i := 0; i < t.NumExplicitMethods(); i++ {
+			if m := t.ExplicitMethod(i); m.Exported() {
+				c.collect(decl, m.Type(), m.Pos())
+			}
+		}
+		for i := 0; i < t.NumEmbeddeds(); i++ {
+			c.collect(decl, t.EmbeddedType(i), pos)
+		}
+	}
+}
+
+func (c *refCollector) add(decl string, obj *types.TypeName, pos token.Pos) {
+	if obj.Pkg() == nil || obj.Pkg() == c.pkg {
+		return
+	}
+	key := refKey{decl: decl, typ: obj}
+	if c.seen[key] {
+		return
+	}
+	c.seen[key] = true
+
+	c.refs = append(c.refs, TypeRef{
+		Package:     c.prog.relPackage(c.pkg.Path()),
+		Decl:        decl,
+		TypePackage: c.prog.relPackage(obj.Pkg().Path()),
+		TypeName:    obj.Name(),
+		Pos:         c.prog.position(pos),
+	})
+}
@@ -0,0 +1,92 @@
+// Package analysis type-checks the packages of a module for rules that cannot be decided
+// from imports alone.
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Program is a set of type-checked packages of one module.
+type Program struct {
+	rootPath   string
+	modulePath string
+	fset       *token.FileSet
+	packages   []*packages.Package
+}
+
+// Load type-checks packages, given relative to rootPath like the packages returned by
+// loader.Load. Packages that fail to type-check are kept with the information that could be
+// computed, and their errors are returned.
+func Load(ctx context.Context, rootPath, modulePath string, pkgs []string) (*Program, []error) {
+	patterns := make([]string, 0, len(pkgs))
+	for _, pkg := range pkgs {
+		patterns = append(patterns, "./"+filepath.ToSlash(pkg))
+	}
+	sort.Strings(patterns)
+
+	absRoot, err := filepath.Abs(rootPath)
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to resolve %s: %w", rootPath, err)}
+	}
+
+	prog := &Program{rootPath: absRoot, modulePath: modulePath, fset: token.NewFileSet()}
+	if len(patterns) == 0 {
+		return prog, nil
+	}
+
+	// NeedDeps type-checks dependencies from source instead of reading their export data,
+	// whose format may be newer than the one golang.org/x/tools understands.
+	cfg := &packages.Config{
+		Context: ctx,
+		Dir:     rootPath,
+		Fset:    prog.fset,
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax |
+			packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedTypesInfo,
+	}
+	loaded, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to load packages: %w", err)}
+	}
+
+	var errs []error
+	for _, pkg := range loaded {
+		for _, e := range pkg.Errors {
+			errs = append(errs, fmt.Errorf("failed to type-check %s: %s", pkg.PkgPath, e.Msg))
+		}
+		if pkg.TypesInfo != nil {
+			prog.packages = append(prog.packages, pkg)
+		}
+	}
+	return prog, errs
+}
+
+// relPackage returns the path of a module package relative to the module root, and the
+// import path of any other package.
+func (p *Program) relPackage(pkgPath string) string {
+	if pkgPath == p.modulePath {
+		return "."
+	}
+	return strings.TrimPrefix(pkgPath, p.modulePath+"/")
+}
+
+// qualifier names packages in type strings like relPackage does.
+func (p *Program) qualifier(pkg *types.Package) string {
+	return p.relPackage(pkg.Path())
+}
+
+// position returns the position of pos with a file name relative to the module root.
+func (p *Program) position(pos token.Pos) token.Position {
+	position := p.fset.Position(pos)
+	if rel, err := filepath.Rel(p.rootPath, position.Filename); err == nil {
+		position.Filename = filepath.ToSlash(rel)
+	}
+	return position
+}
@@ -0,0 +1,138 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type AnalysisSuite struct {
+	suite.Suite
+	root string
+}
+
+func TestAnalysisSuite(t *testing.T) {
+	suite.Run(t, new(AnalysisSuite))
+}
+
+func (s *AnalysisSuite) SetupTest() {
+	s.root = s.T().TempDir()
+	s.write("go.mod", "module github.com/example/app\n\ngo 1.22\n")
+}
+
+func (s *AnalysisSuite) write(name, content string) {
+	path := filepath.Join(s.root, name)
+	s.Require().NoError(os.MkdirAll(filepath.Dir(path), 0o755))
+	s.Require().NoError(os.WriteFile(path, []byte(content), 0o644))
+}
+
+func (s *AnalysisSuite) TestCalls_ResolvesAliasesAndMethodValues() {
+	// given
+	s.write("internal/clock/clock.go", "package clock\n\ntype Clock struct{}\n\nfunc (*Clock) Now() int { return 0 }\n")
+	s.write("internal/domain/user.go", `package domain
+
+import (
+	stdlog "log"
+
+	"github.com/example/app/internal/clock"
+)
+
+func Run(logger *stdlog.Logger, c *clock.Clock) {
+	fatal := logger.Fatal
+	fatal("stop")
+	stdlog.Println(c.Now())
+	panic("unreachable")
+}
+`)
+
+	// when
+	prog, errs := Load(context.Background(), s.root, "github.com/example/app", []string{"internal/domain"})
+
+	// then
+	assert.Empty(s.T(), errs)
+	s.Require().NotNil(prog)
+	var found []string
+	for _, call := range prog.Calls() {
+		assert.Equal(s.T(), "internal/domain", call.Package)
+		assert.Equal(s.T(), "internal/domain/user.go", call.Pos.Filename)
+		found = append(found, fmt.Sprintf("%s@%d", call.Name, call.Pos.Line))
+	}
+	assert.Equal(s.T(), []string{
+		"(*log.Logger).Fatal@10",
+		"log.Println@12",
+		"(*internal/clock.Clock).Now@12",
+		"panic@13",
+	}, found)
+}
+
+func (s *AnalysisSuite) TestCalls_GenericMethodsNamedByTypeParameters() {
+	// given
+	s.write("internal/list/list.go", "package list\n\ntype List[T any] struct{}\n\nfunc (*List[T]) Push(T) {}\n")
+	s.write("internal/domain/user.go", `package domain
+
+import "github.com/example/app/internal/list"
+
+func Run(l *list.List[string]) {
+	l.Push("a")
+}
+`)
+
+	// when
+	prog, errs := Load(context.Background(), s.root, "github.com/example/app", []string{"internal/domain"})
+
+	// then
+	assert.Empty(s.T(), errs)
+	s.Require().NotNil(prog)
+	s.Require().Len(prog.Calls(), 1)
+	assert.Equal(s.T(), "(*internal/list.List[T]).Push", prog.Calls()[0].Name)
+}
+
+func (s *AnalysisSuite) TestExportedTypeRefs_SignaturesFieldsAndAliases() {
+	// given
+	s.write("internal/store/store.go", "package store\n\ntype DB struct{}\n\ntype Row struct{}\n")
+	s.write("internal/domain/user.go", `package domain
+
+import (
+	"context"
+
+	"github.com/example/app/internal/store"
+)
+
+type Handle = store.DB
+
+type User struct {
+	Name string
+	Rows []store.Row
+	db   *store.DB
+}
+
+func (u *User) Save(ctx context.Context) error { return nil }
+
+func New(db *store.DB) *User { return &User{db: db} }
+
+func helper(db *store.DB) {}
+`)
+
+	// when
+	prog, errs := Load(context.Background(), s.root, "github.com/example/app", []string{"internal/domain"})
+
+	// then
+	assert.Empty(s.T(), errs)
+	s.Require().NotNil(prog)
+	var found []string
+	for _, ref := range prog.ExportedTypeRefs() {
+		assert.Equal(s.T(), "internal/domain", ref.Package)
+		found = append(found, fmt.Sprintf("%s: %s.%s@%d", ref.Decl, ref.TypePackage, ref.TypeName, ref.Pos.Line))
+	}
+	assert.ElementsMatch(s.T(), []string{
+		"Handle: internal/store.DB@9",
+		"New: internal/store.DB@19",
+		"User.Rows: internal/store.Row@13",
+		"User.Save: context.Context@17",
+	}, found)
+}
@@ -0,0 +1,106 @@
+package checker
+
+import (
+	"fmt"
+	"go/token"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/coderhyme/arch-lint/internal/config"
+	"github.com/coderhyme/arch-lint/internal/loader"
+	"github.com/gobwas/glob"
+)
+
+// CheckAliases checks the names files import packages by against the config's aliases.
+// files holds the imports of each file keyed by its path relative to the module root; only
+// files of packageImports are checked. Module packages are matched by their path relative to
+// the module root, others by their full import path. packageName resolves the name of imports
+// that are not renamed. Dot and blank imports are left to import form rules. An import matched
+// by patterns requiring different names is an error.
+func CheckAliases(modulePath string, files map[string][]loader.Import, packageImports map[string]map[string]struct{}, aliases map[string]*config.Alias, packageName func(importPath string) string) ([]Violation, error) {
+	type aliasRule struct {
+		pattern string
+		g       glob.Glob
+		alias   *config.Alias
+	}
+
+	patterns := make([]string, 0, len(aliases))
+	for pattern := range aliases {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	rules := make([]aliasRule, 0, len(patterns))
+	for _, pattern := range patterns {
+		g, err := glob.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid alias pattern %q: %w", pattern, err)
+		}
+		rules = append(rules, aliasRule{pattern: pattern, g: g, alias: aliases[pattern]})
+	}
+
+	paths := make([]string, 0, len(files))
+	for relPath := range files {
+		paths = append(paths, relPath)
+	}
+	sort.Strings(paths)
+
+	var violations []Violation
+	for _, relPath := range paths {
+		pkgPath := filepath.Dir(relPath)
+		if _, ok := packageImports[pkgPath]; !ok {
+			continue
+		}
+
+		for _, imp := range files[relPath] {
+			name := imp.Name
+			if name == "" {
+				name = packageName(imp.Path)
+			}
+			if name == "_" || name == "." {
+				continue
+			}
+
+			importPath := imp.Path
+			if relImport, ok := StripModulePrefix(modulePath, imp.Path); ok {
+				importPath = relImport
+			}
+
+			var required *aliasRule
+			for _, rule := range rules {
+				if !rule.g.Match(importPath) {
+					continue
+				}
+				if rule.alias.Name != "" {
+					if required != nil && required.alias.Name != rule.alias.Name {
+						return nil, fmt.Errorf("aliases %s and %s both match %s but require different names %q and %q",
+							required.pattern, rule.pattern, importPath, required.alias.Name, rule.alias.Name)
+					}
+					required = &rule
+				}
+
+				v := Violation{
+					Kind:     KindAlias,
+					Package:  pkgPath,
+					Import:   importPath,
+					Severity: rule.alias.Severity.Or(config.SeverityError),
+					Source:   rule.alias.Source,
+					Alias:    name,
+					Pos:      token.Position{Filename: filepath.ToSlash(relPath), Line: imp.Line},
+				}
+				switch {
+				case rule.alias.Name != "" && name != rule.alias.Name:
+					v.Rule = fmt.Sprintf("alias %q required", rule.alias.Name)
+					v.WantAlias = rule.alias.Name
+				case slices.Contains(rule.alias.Forbidden, name):
+					v.Rule = fmt.Sprintf("alias %q forbidden", name)
+				default:
+					continue
+				}
+				violations = append(violations, v)
+			}
+		}
+	}
+	return violations, nil
+}
@@ -2,20 +2,147 @@ package checker
 
 import (
 	"context"
+	"fmt"
+	"go/token"
 	"strings"
+	"time"
 
+	"github.com/coderhyme/arch-lint/internal/analysis"
+	"github.com/coderhyme/arch-lint/internal/config"
 	"github.com/coderhyme/arch-lint/internal/groups"
+	"github.com/coderhyme/arch-lint/internal/loader"
+)
+
+// Kind is the kind of rule a violation breaks. It decides which of the fields that describe
+// the violation's target are set.
+type Kind string
+
+const (
+	// KindImport is an import denied by the importing package's group.
+	KindImport Kind = "import"
+	// KindPrivate is an import of a package of the private group GroupName from outside it.
+	KindPrivate Kind = "private"
+	// KindTransitive is a package reached through a chain of imports, described by Chain.
+	KindTransitive Kind = "transitive"
+	// KindSymbol is a use of the identifier Symbol of Import, found at Pos.
+	KindSymbol Kind = "symbol"
+	// KindCall is a forbidden call of Call, found at Pos.
+	KindCall Kind = "call"
+	// KindExport is an exported declaration Decl exposing the type Import.Symbol, found at Pos.
+	KindExport Kind = "export"
+	// KindImportForm is an import of Import in the forbidden form Form, found at Pos.
+	KindImportForm Kind = "import form"
+	// KindAlias is an import of Import by the name Alias, found at Pos.
+	KindAlias Kind = "alias"
+	// KindLinkname is the //go:linkname directive Directive, found at Pos.
+	KindLinkname Kind = "linkname"
+	// KindUnsafe is a use of the identifier Symbol of package unsafe, found at Pos.
+	KindUnsafe Kind = "unsafe"
+	// KindLimit is a group over maxImports or maxImporters, described by Count and Offenders.
+	KindLimit Kind = "limit"
 )
 
 type Violation struct {
+	Kind      Kind
 	Package   string
 	Import    string
 	GroupName string
+	Rule      string
+	Severity  config.Severity
+	// Source is the config file that declared the group.
+	Source string
+	// Message and Docs are the explanation configured on the violated rule, if any.
+	Message string
+	Docs    string
+	// Symbol is the used identifier of Import for violations of symbol rules, found at Pos.
+	Symbol string
+	// Call is the called function for violations of forbidden calls, found at Pos.
+	Call string
+	// Decl is the exported declaration that exposes the type Import.Symbol for violations
+	// of export rules, found at Pos.
+	Decl string
+	Pos  token.Position
+	// Chain is the import chain from Package to Import for violations of transitive rules,
+	// joined by " -> ".
+	Chain string
+	// Alias is the name Import is imported by for violations of aliases, found at Pos, and
+	// WantAlias the name it must be imported by, if any. Alias violations have no group.
+	Alias     string
+	WantAlias string
+	// Directive is the //go:linkname directive for violations of unsafe rules, found at Pos.
+	// Uses of package unsafe are reported like symbol uses.
+	Directive string
+	// Form is the forbidden import form for violations of import form rules, found at Pos.
+	Form string
+	// Count is the number of packages counted for violations of maxImports and
+	// maxImporters, and Offenders lists the group's packages contributing the most.
+	Count     int
+	Offenders string
+}
+
+// Target is the import, the used identifier for violations of symbol and unsafe rules and
+// for exported declarations, the called function for forbidden calls, the import and its form
+// for forbidden import forms, the import and its name for aliases, the directive for
+// //go:linkname, or the number of packages counted for limits.
+func (v Violation) Target() string {
+	switch v.Kind {
+	case KindLinkname:
+		return v.Directive
+	case KindImportForm:
+		return v.Import + " (" + v.Form + ")"
+	case KindAlias:
+		return v.Import + " as " + v.Alias
+	case KindLimit:
+		return fmt.Sprintf("%d packages", v.Count)
+	case KindCall:
+		return v.Call
+	case KindSymbol, KindUnsafe, KindExport:
+		return v.Import + "." + v.Symbol
+	default:
+		return v.Import
+	}
+}
+
+// DeniedBy names what the violation breaks: the group's rules, the config's aliases, or, for
+// packages without a group, the groups designated with allowUnsafe.
+func (v Violation) DeniedBy() string {
+	switch {
+	case v.Kind == KindAlias:
+		return "aliases"
+	case v.GroupName == "":
+		return "allowUnsafe"
+	default:
+		return fmt.Sprintf("group %q", v.GroupName)
+	}
+}
+
+// isEdge reports whether the violation is of an import edge from Package to Import.
+func (v Violation) isEdge() bool {
+	return v.Kind == KindImport || v.Kind == KindPrivate || v.Kind == KindTransitive
+}
+
+// Explanation joins the message and docs of the violated rule into one line, or returns
+// an empty string when the rule has neither.
+func (v Violation) Explanation() string {
+	switch {
+	case v.Message != "" && v.Docs != "":
+		return v.Message + " (see " + v.Docs + ")"
+	case v.Docs != "":
+		return "see " + v.Docs
+	default:
+		return v.Message
+	}
 }
 
 type Result struct {
 	Violations    []Violation
 	PackagesCount int
+	// Suppressed are the violations covered by an exception that has not expired.
+	Suppressed []Violation
+	// ExpiredExceptions no longer suppress violations, and UnusedExceptions matched no
+	// violation, so both should be removed from the config.
+	ExpiredExceptions []config.Exception
+	UnusedExceptions  []config.Exception
 }
 
 func Check(ctx context.Context, modulePath string, packageImports map[string]map[string]struct{}, manager groups.GroupManager) (*Result, error) {
@@ -34,18 +161,24 @@ func Check(ctx context.Context, modulePath string, packageImports map[string]map
 		}
 
 		for imp := range imports {
-			relImport, ok := stripModulePrefix(modulePath, imp)
+			relImport, ok := StripModulePrefix(modulePath, imp)
 			if !ok {
 				continue
 			}
 
 			for _, grp := range matchingGroups {
 				checker := grp.GetDependencyChecker(pkgPath)
-				if !checker.CanDependOn(relImport) {
+				if decision := checker.Decide(relImport); !decision.Allowed {
 					result.Violations = append(result.Violations, Violation{
+						Kind:      KindImport,
 						Package:   pkgPath,
 						Import:    relImport,
 						GroupName: grp.Name(),
+						Rule:      decision.Rule,
+						Severity:  decision.Severity,
+						Source:    grp.Source(),
+						Message:   decision.Message,
+						Docs:      decision.Docs,
 					})
 				}
 			}
@@ -55,9 +188,152 @@ func Check(ctx context.Context, modulePath string, packageImports map[string]map
 	return result, nil
 }
 
-func stripModulePrefix(modulePath, importPath string) (string, bool) {
+// CheckSymbols checks identifier uses against the symbol rules of the groups of the using
+// packages. Identifiers of module packages are matched by their path relative to the module,
+// others by their full import path.
+func CheckSymbols(ctx context.Context, modulePath string, uses []loader.SymbolUse, manager groups.GroupManager) ([]Violation, error) {
+	var violations []Violation
+	for _, use := range uses {
+		matchingGroups, err := manager.GetGroups(ctx, use.Package)
+		if err != nil {
+			return nil, err
+		}
+
+		importPath := use.Import
+		if relImport, ok := StripModulePrefix(modulePath, use.Import); ok {
+			importPath = relImport
+		}
+
+		for _, grp := range matchingGroups {
+			checker := grp.GetDependencyChecker(use.Package)
+			if decision := checker.DecideSymbol(importPath, use.Name); !decision.Allowed {
+				violations = append(violations, Violation{
+					Kind:      KindSymbol,
+					Package:   use.Package,
+					Import:    importPath,
+					GroupName: grp.Name(),
+					Rule:      decision.Rule,
+					Severity:  decision.Severity,
+					Source:    grp.Source(),
+					Message:   decision.Message,
+					Docs:      decision.Docs,
+					Symbol:    use.Name,
+					Pos:       use.Pos,
+				})
+			}
+		}
+	}
+	return violations, nil
+}
+
+// CheckCalls checks calls against the forbidden calls of the groups of the calling packages.
+func CheckCalls(ctx context.Context, calls []analysis.Call, manager groups.GroupManager) ([]Violation, error) {
+	var violations []Violation
+	for _, call := range calls {
+		matchingGroups, err := manager.GetGroups(ctx, call.Package)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, grp := range matchingGroups {
+			checker := grp.GetDependencyChecker(call.Package)
+			if decision := checker.DecideCall(call.Name); !decision.Allowed {
+				violations = append(violations, Violation{
+					Kind:      KindCall,
+					Package:   call.Package,
+					GroupName: grp.Name(),
+					Rule:      decision.Rule,
+					Severity:  decision.Severity,
+					Source:    grp.Source(),
+					Call:      call.Name,
+					Pos:       call.Pos,
+				})
+			}
+		}
+	}
+	return violations, nil
+}
+
+// CheckExports checks the types referenced by exported APIs against the export rules of
+// the groups of the declaring packages.
+func CheckExports(ctx context.Context, refs []analysis.TypeRef, manager groups.GroupManager) ([]Violation, error) {
+	var violations []Violation
+	for _, ref := range refs {
+		matchingGroups, err := manager.GetGroups(ctx, ref.Package)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, grp := range matchingGroups {
+			checker := grp.GetDependencyChecker(ref.Package)
+			if decision := checker.DecideExport(ref.TypePackage); !decision.Allowed {
+				violations = append(violations, Violation{
+					Kind:      KindExport,
+					Package:   ref.Package,
+					Import:    ref.TypePackage,
+					GroupName: grp.Name(),
+					Rule:      decision.Rule,
+					Severity:  decision.Severity,
+					Source:    grp.Source(),
+					Message:   decision.Message,
+					Docs:      decision.Docs,
+					Symbol:    ref.TypeName,
+					Decl:      ref.Decl,
+					Pos:       ref.Pos,
+				})
+			}
+		}
+	}
+	return violations, nil
+}
+
+func StripModulePrefix(modulePath, importPath string) (string, bool) {
 	if !strings.HasPrefix(importPath, modulePath+"/") {
 		return "", false
 	}
 	return strings.TrimPrefix(importPath, modulePath+"/"), true
 }
+
+// ApplyExceptions moves the violations covered by exceptions that have not expired at now
+// from Violations to Suppressed, and records the exceptions that expired or went unused.
+// Exceptions describe import edges, so they only cover import violations, including those of
+// private groups and transitive rules. Exceptions for packages outside packageImports were
+// not exercised and are never unused.
+func (r *Result) ApplyExceptions(exceptions []config.Exception, packageImports map[string]map[string]struct{}, now time.Time) {
+	used := make([]bool, len(exceptions))
+	var remaining []Violation
+
+	for _, v := range r.Violations {
+		suppressed := false
+		for i, e := range exceptions {
+			if v.isEdge() && e.Matches(v.Package, v.Import) && !e.Expired(now) {
+				used[i] = true
+				suppressed = true
+			}
+		}
+		if suppressed {
+			r.Suppressed = append(r.Suppressed, v)
+		} else {
+			remaining = append(remaining, v)
+		}
+	}
+	r.Violations = remaining
+
+	for i, e := range exceptions {
+		if e.Expired(now) {
+			r.ExpiredExceptions = append(r.ExpiredExceptions, e)
+		} else if _, checked := packageImports[e.Package]; checked && !used[i] {
+			r.UnusedExceptions = append(r.UnusedExceptions, e)
+		}
+	}
+}
+
+// Fails reports whether any violation is at least as severe as threshold.
+func (r *Result) Fails(threshold config.Severity) bool {
+	for _, v := range r.Violations {
+		if v.Severity.AtLeast(threshold) {
+			return true
+		}
+	}
+	return false
+}
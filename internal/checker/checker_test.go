@@ -2,10 +2,14 @@ package checker
 
 import (
 	"context"
+	"path"
 	"testing"
+	"time"
 
+	"github.com/coderhyme/arch-lint/internal/analysis"
 	"github.com/coderhyme/arch-lint/internal/config"
 	"github.com/coderhyme/arch-lint/internal/groups"
+	"github.com/coderhyme/arch-lint/internal/loader"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
@@ -262,13 +266,1051 @@ func (s *CheckerSuite) TestAllowOnlyMode_UnmatchedImportDenied() {
 
 func (s *CheckerSuite) TestStripModulePrefix() {
 	// given/when/then
-	rel, ok := stripModulePrefix("github.com/example/app", "github.com/example/app/internal/domain")
+	rel, ok := StripModulePrefix("github.com/example/app", "github.com/example/app/internal/domain")
 	assert.True(s.T(), ok)
 	assert.Equal(s.T(), "internal/domain", rel)
 
-	_, ok = stripModulePrefix("github.com/example/app", "github.com/other/lib")
+	_, ok = StripModulePrefix("github.com/example/app", "github.com/other/lib")
 	assert.False(s.T(), ok)
 
-	_, ok = stripModulePrefix("github.com/example/app", "fmt")
+	_, ok = StripModulePrefix("github.com/example/app", "fmt")
 	assert.False(s.T(), ok)
 }
+
+func (s *CheckerSuite) TestLayers_UpwardImportIsLayerViolation() {
+	// given
+	cfg, err := config.LoadFromBytes([]byte(`
+version: 1
+layers:
+  mode: strict
+  groups: [api, service, domain]
+groups:
+  api:
+    paths: "internal/api/**"
+  service:
+    paths: "internal/service/**"
+  domain:
+    paths: "internal/domain/**"
+`))
+	s.Require().NoError(err)
+	manager, err := groups.NewGroupManager(cfg)
+	s.Require().NoError(err)
+
+	packages := map[string]map[string]struct{}{
+		"internal/api/user": {
+			"github.com/example/app/internal/service/user": {},
+			"github.com/example/app/internal/domain/user":  {},
+		},
+		"internal/service/user": {
+			"github.com/example/app/internal/service/order": {},
+			"github.com/example/app/internal/domain/user":   {},
+		},
+		"internal/domain/user": {
+			"github.com/example/app/internal/api/user": {},
+		},
+	}
+
+	// when
+	result, err := Check(context.Background(), "github.com/example/app", packages, manager)
+
+	// then - api skips service in strict mode, domain imports upward
+	assert.NoError(s.T(), err)
+	s.Require().Len(result.Violations, 2)
+	rules := map[string]string{}
+	for _, v := range result.Violations {
+		rules[v.Package] = v.Rule
+	}
+	assert.Equal(s.T(), `layer violation: layer "api" skips layers to import "domain"`, rules["internal/api/user"])
+	assert.Equal(s.T(), `layer violation: layer "domain" imports higher layer "api"`, rules["internal/domain/user"])
+}
+
+func (s *CheckerSuite) TestLayers_RelaxedAllowsAllLowerLayers() {
+	// given
+	cfg, err := config.LoadFromBytes([]byte(`
+version: 1
+layers: [api, service, domain]
+groups:
+  api:
+    paths: "internal/api/**"
+  service:
+    paths: "internal/service/**"
+  domain:
+    paths: "internal/domain/**"
+`))
+	s.Require().NoError(err)
+	manager, err := groups.NewGroupManager(cfg)
+	s.Require().NoError(err)
+
+	packages := map[string]map[string]struct{}{
+		"internal/api/user": {
+			"github.com/example/app/internal/domain/user": {},
+		},
+	}
+
+	// when
+	result, err := Check(context.Background(), "github.com/example/app", packages, manager)
+
+	// then
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), result.Violations)
+}
+
+func (s *CheckerSuite) TestLayers_WithoutRulesImportOtherGroupsFreely() {
+	// given
+	cfg, err := config.LoadFromBytes([]byte(`
+version: 1
+layers: [api, domain]
+groups:
+  api:
+    paths: "internal/api/**"
+  domain:
+    paths: "internal/domain/**"
+  shared:
+    paths: "internal/shared/**"
+`))
+	s.Require().NoError(err)
+	manager, err := groups.NewGroupManager(cfg)
+	s.Require().NoError(err)
+
+	packages := map[string]map[string]struct{}{
+		"internal/domain/user": {
+			"github.com/example/app/internal/shared/clock": {},
+			"fmt": {},
+		},
+	}
+
+	// when
+	result, err := Check(context.Background(), "github.com/example/app", packages, manager)
+
+	// then
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), result.Violations)
+}
+
+func (s *CheckerSuite) TestLayers_AllowRulesKeepLowerLayers() {
+	// given
+	cfg, err := config.LoadFromBytes([]byte(`
+version: 1
+layers: [api, domain]
+groups:
+  api:
+    paths: "internal/api/**"
+    dependencies:
+      allow:
+        patterns: ["fmt"]
+  domain:
+    paths: "internal/domain/**"
+  shared:
+    paths: "internal/shared/**"
+`))
+	s.Require().NoError(err)
+	manager, err := groups.NewGroupManager(cfg)
+	s.Require().NoError(err)
+
+	packages := map[string]map[string]struct{}{
+		"internal/api/user": {
+			"github.com/example/app/internal/domain/user":  {},
+			"github.com/example/app/internal/shared/clock": {},
+			"fmt": {},
+		},
+	}
+
+	// when
+	result, err := Check(context.Background(), "github.com/example/app", packages, manager)
+
+	// then - the lower layer is added to the allow list, shared is not on it
+	assert.NoError(s.T(), err)
+	s.Require().Len(result.Violations, 1)
+	assert.Equal(s.T(), "internal/shared/clock", result.Violations[0].Import)
+	assert.Equal(s.T(), "not allowed", result.Violations[0].Rule)
+}
+
+func (s *CheckerSuite) TestCaptureVariables_SiblingModulesIsolated() {
+	// given
+	cfg := &config.Config{
+		Version: 1,
+		Groups: map[string]*config.Group{
+			"shared": {
+				Paths: config.PathConfigs{{Dir: "internal/shared{,/**}"}},
+			},
+			"modules": {
+				Paths: config.PathConfigs{{Dir: "internal/modules/{module}{,/**}"}},
+				Dependencies: &config.Dependencies{
+					Allow: &config.DependencyRule{
+						Groups:   []string{"shared"},
+						Patterns: []string{"internal/modules/{module}{,/**}"},
+					},
+				},
+			},
+		},
+	}
+	manager, err := groups.NewGroupManager(cfg)
+	s.Require().NoError(err)
+
+	packages := map[string]map[string]struct{}{
+		"internal/modules/billing/api": {
+			"github.com/example/app/internal/modules/billing/domain": {},
+			"github.com/example/app/internal/modules/billing":        {},
+			"github.com/example/app/internal/shared/money":           {},
+			"github.com/example/app/internal/modules/shipping/api":   {},
+		},
+	}
+
+	// when
+	result, err := Check(context.Background(), "github.com/example/app", packages, manager)
+
+	// then - only the sibling module import is a violation
+	assert.NoError(s.T(), err)
+	s.Require().Len(result.Violations, 1)
+	assert.Equal(s.T(), "internal/modules/shipping/api", result.Violations[0].Import)
+}
+
+func (s *CheckerSuite) TestCaptureVariables_UnknownVariableRejected() {
+	// given
+	cfg := &config.Config{
+		Version: 1,
+		Groups: map[string]*config.Group{
+			"modules": {
+				Paths: config.PathConfigs{{Dir: "internal/modules/{module}/**"}},
+				Dependencies: &config.Dependencies{
+					Allow: &config.DependencyRule{
+						Patterns: []string{"internal/modules/{name}/**"},
+					},
+				},
+			},
+		},
+	}
+
+	// when
+	_, err := groups.NewGroupManager(cfg)
+
+	// then
+	assert.ErrorContains(s.T(), err, "{name}")
+}
+
+func (s *CheckerSuite) TestCaptureVariables_MalformedPatternRejected() {
+	// given
+	cfg := &config.Config{
+		Version: 1,
+		Groups: map[string]*config.Group{
+			"modules": {
+				Paths: config.PathConfigs{{Dir: "internal/modules/{module}/**"}},
+				Dependencies: &config.Dependencies{
+					Allow: &config.DependencyRule{
+						Patterns: []string{"internal/modules/{module}/[api/**"},
+					},
+				},
+			},
+		},
+	}
+
+	// when
+	_, err := groups.NewGroupManager(cfg)
+
+	// then
+	assert.ErrorContains(s.T(), err, "failed to build group modules")
+	assert.ErrorContains(s.T(), err, `invalid pattern "internal/modules/{module}/[api/**"`)
+}
+
+func (s *CheckerSuite) TestSeverity_RuleOverridesGroup() {
+	// given
+	cfg := &config.Config{
+		Version: 1,
+		Groups: map[string]*config.Group{
+			"repository": {
+				Paths: config.PathConfigs{{Dir: "internal/repository/**"}},
+			},
+			"api": {
+				Paths:    config.PathConfigs{{Dir: "internal/api/**"}},
+				Severity: config.SeverityWarning,
+				Dependencies: &config.Dependencies{
+					Deny: &config.DependencyRule{
+						Groups:   []string{"repository"},
+						Severity: config.SeverityInfo,
+					},
+					Allow: &config.DependencyRule{
+						Patterns: []string{"internal/domain/**"},
+					},
+				},
+			},
+		},
+	}
+	manager, err := groups.NewGroupManager(cfg)
+	s.Require().NoError(err)
+
+	packages := map[string]map[string]struct{}{
+		"internal/api/user": {
+			"github.com/example/app/internal/repository/sql": {},
+			"github.com/example/app/internal/legacy/db":      {},
+		},
+	}
+
+	// when
+	result, err := Check(context.Background(), "github.com/example/app", packages, manager)
+
+	// then
+	assert.NoError(s.T(), err)
+	s.Require().Len(result.Violations, 2)
+	severities := map[string]config.Severity{}
+	for _, v := range result.Violations {
+		severities[v.Import] = v.Severity
+	}
+	assert.Equal(s.T(), config.SeverityInfo, severities["internal/repository/sql"])
+	assert.Equal(s.T(), config.SeverityWarning, severities["internal/legacy/db"])
+	assert.True(s.T(), result.Fails(config.SeverityWarning))
+	assert.False(s.T(), result.Fails(config.SeverityError))
+}
+
+func (s *CheckerSuite) TestSeverity_DefaultsToError() {
+	// given
+	cfg := &config.Config{
+		Version: 1,
+		Groups: map[string]*config.Group{
+			"domain": {
+				Paths: config.PathConfigs{{Dir: "internal/domain/**"}},
+				Dependencies: &config.Dependencies{
+					Allow: &config.DependencyRule{
+						Patterns: []string{"internal/shared/**"},
+					},
+				},
+			},
+		},
+	}
+	manager, err := groups.NewGroupManager(cfg)
+	s.Require().NoError(err)
+
+	packages := map[string]map[string]struct{}{
+		"internal/domain/user": {
+			"github.com/example/app/internal/api/user": {},
+		},
+	}
+
+	// when
+	result, err := Check(context.Background(), "github.com/example/app", packages, manager)
+
+	// then
+	assert.NoError(s.T(), err)
+	s.Require().Len(result.Violations, 1)
+	assert.Equal(s.T(), config.SeverityError, result.Violations[0].Severity)
+}
+
+func (s *CheckerSuite) TestMessages_PatternOverridesRule() {
+	// given
+	cfg, err := config.LoadFromBytes([]byte(`
+version: 1
+groups:
+  api:
+    paths: "internal/api/**"
+    dependencies:
+      deny:
+        message: "API handlers must not reach into internals"
+        patterns:
+          - "internal/platform/**"
+          - pattern: "internal/repository/**"
+            message: "Go through the service layer"
+            docs: "https://example.com/adr/7"
+      allow:
+        patterns: ["internal/**"]
+`))
+	s.Require().NoError(err)
+	manager, err := groups.NewGroupManager(cfg)
+	s.Require().NoError(err)
+
+	packages := map[string]map[string]struct{}{
+		"internal/api/user": {
+			"github.com/example/app/internal/repository/sql": {},
+			"github.com/example/app/internal/platform/db":    {},
+		},
+	}
+
+	// when
+	result, err := Check(context.Background(), "github.com/example/app", packages, manager)
+
+	// then
+	assert.NoError(s.T(), err)
+	s.Require().Len(result.Violations, 2)
+	byImport := map[string]Violation{}
+	for _, v := range result.Violations {
+		byImport[v.Import] = v
+	}
+	assert.Equal(s.T(), "Go through the service layer (see https://example.com/adr/7)", byImport["internal/repository/sql"].Explanation())
+	assert.Equal(s.T(), "API handlers must not reach into internals", byImport["internal/platform/db"].Explanation())
+}
+
+func (s *CheckerSuite) TestApplyExceptions() {
+	// given
+	cfg := &config.Config{
+		Version: 1,
+		Groups: map[string]*config.Group{
+			"api": {
+				Paths: config.PathConfigs{{Dir: "internal/api/**"}},
+				Dependencies: &config.Dependencies{
+					Deny: &config.DependencyRule{
+						Patterns: []string{"internal/repository/**"},
+					},
+					Allow: &config.DependencyRule{
+						Patterns: []string{"internal/**"},
+					},
+				},
+			},
+		},
+	}
+	manager, err := groups.NewGroupManager(cfg)
+	s.Require().NoError(err)
+
+	packages := map[string]map[string]struct{}{
+		"internal/api/user": {
+			"github.com/example/app/internal/repository/user": {},
+		},
+		"internal/api/order": {
+			"github.com/example/app/internal/repository/order": {},
+		},
+	}
+	exceptions := []config.Exception{
+		{Package: "internal/api/user", Import: "internal/repository/user", Expires: "2025-06-30"},
+		{Package: "internal/api/order", Import: "internal/repository/order", Expires: "2025-05-31"},
+		{Package: "internal/api/user", Import: "internal/repository/admin", Expires: "2025-06-30"},
+		{Package: "internal/api/billing", Import: "internal/repository/billing", Expires: "2025-06-30"},
+	}
+	result, err := Check(context.Background(), "github.com/example/app", packages, manager)
+	s.Require().NoError(err)
+
+	// when
+	result.ApplyExceptions(exceptions, packages, time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC))
+
+	// then
+	s.Require().Len(result.Violations, 1)
+	assert.Equal(s.T(), "internal/api/order", result.Violations[0].Package)
+	s.Require().Len(result.Suppressed, 1)
+	assert.Equal(s.T(), "internal/api/user", result.Suppressed[0].Package)
+	assert.Equal(s.T(), []config.Exception{exceptions[1]}, result.ExpiredExceptions)
+	assert.Equal(s.T(), []config.Exception{exceptions[2]}, result.UnusedExceptions)
+}
+
+func (s *CheckerSuite) TestApplyExceptions_OnlyImportEdges() {
+	// given
+	result := &Result{Violations: []Violation{
+		{Kind: KindImport, Package: "internal/api/user", Import: "internal/legacy", GroupName: "api"},
+		{Kind: KindPrivate, Package: "internal/api/user", Import: "internal/legacy", GroupName: "legacy"},
+		{Kind: KindTransitive, Package: "internal/api/user", Import: "internal/legacy", GroupName: "api"},
+		{Kind: KindSymbol, Package: "internal/api/user", Import: "internal/legacy", Symbol: "DB", GroupName: "api"},
+		{Kind: KindImportForm, Package: "internal/api/user", Import: "internal/legacy", Form: "dot", GroupName: "api"},
+		{Kind: KindAlias, Package: "internal/api/user", Import: "internal/legacy", Alias: "l"},
+	}}
+	exceptions := []config.Exception{
+		{Package: "internal/api/user", Import: "internal/legacy", Expires: "2025-06-30"},
+	}
+	packages := map[string]map[string]struct{}{"internal/api/user": {}}
+
+	// when
+	result.ApplyExceptions(exceptions, packages, time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC))
+
+	// then
+	var suppressed, remaining []Kind
+	for _, v := range result.Suppressed {
+		suppressed = append(suppressed, v.Kind)
+	}
+	for _, v := range result.Violations {
+		remaining = append(remaining, v.Kind)
+	}
+	assert.Equal(s.T(), []Kind{KindImport, KindPrivate, KindTransitive}, suppressed)
+	assert.Equal(s.T(), []Kind{KindSymbol, KindImportForm, KindAlias}, remaining)
+	assert.Empty(s.T(), result.UnusedExceptions)
+}
+
+func (s *CheckerSuite) TestCheckSymbols_AllowAndDenyIdentifiers() {
+	// given
+	cfg := &config.Config{
+		Version: 1,
+		Groups: map[string]*config.Group{
+			"api": {
+				Paths: config.PathConfigs{{Dir: "internal/api/**"}},
+				Dependencies: &config.Dependencies{
+					Deny: &config.DependencyRule{
+						Symbols: []string{"internal/legacy.DB"},
+					},
+					Allow: &config.DependencyRule{
+						Patterns: []string{"internal/legacy"},
+						Symbols:  []string{"internal/domain.User", "internal/domain.New*"},
+					},
+				},
+			},
+		},
+	}
+	manager, err := groups.NewGroupManager(cfg)
+	s.Require().NoError(err)
+
+	use := func(imp, name string) loader.SymbolUse {
+		return loader.SymbolUse{Package: "internal/api/user", Import: "github.com/example/app/" + imp, Name: name}
+	}
+	uses := []loader.SymbolUse{
+		use("internal/domain", "User"),
+		use("internal/domain", "NewUser"),
+		use("internal/domain", "hashPassword"),
+		use("internal/legacy", "DB"),
+		use("internal/legacy", "Open"),
+	}
+
+	// when
+	violations, err := CheckSymbols(context.Background(), "github.com/example/app", uses, manager)
+
+	// then
+	assert.NoError(s.T(), err)
+	s.Require().Len(violations, 2)
+	assert.Equal(s.T(), "internal/domain.hashPassword", violations[0].Target())
+	assert.Equal(s.T(), "symbol not allowed", violations[0].Rule)
+	assert.Equal(s.T(), "internal/legacy.DB", violations[1].Target())
+	assert.Equal(s.T(), `deny symbol "internal/legacy.DB"`, violations[1].Rule)
+
+	api, err := manager.GetGroup(context.Background(), "api")
+	s.Require().NoError(err)
+	assert.True(s.T(), api.GetDependencyChecker("internal/api/user").CanDependOn("internal/domain"))
+}
+
+func (s *CheckerSuite) TestCheckCalls_ForbiddenCalls() {
+	// given
+	cfg := &config.Config{
+		Version: 1,
+		Groups: map[string]*config.Group{
+			"domain": {
+				Paths:          config.PathConfigs{{Dir: "internal/domain/**"}},
+				ForbiddenCalls: []string{"os.Exit", "(*log.Logger).Fatal*", "panic"},
+			},
+		},
+	}
+	manager, err := groups.NewGroupManager(cfg)
+	s.Require().NoError(err)
+
+	calls := []analysis.Call{
+		{Package: "internal/domain/user", Name: "os.Exit"},
+		{Package: "internal/domain/user", Name: "(*log.Logger).Fatalf"},
+		{Package: "internal/domain/user", Name: "(log.Logger).Fatal"},
+		{Package: "internal/domain/user", Name: "fmt.Println"},
+		{Package: "internal/api/user", Name: "panic"},
+	}
+
+	// when
+	violations, err := CheckCalls(context.Background(), calls, manager)
+
+	// then
+	assert.NoError(s.T(), err)
+	s.Require().Len(violations, 2)
+	assert.Equal(s.T(), "os.Exit", violations[0].Target())
+	assert.Equal(s.T(), `forbidden call "os.Exit"`, violations[0].Rule)
+	assert.Equal(s.T(), "(*log.Logger).Fatalf", violations[1].Target())
+	assert.Equal(s.T(), config.SeverityError, violations[1].Severity)
+}
+
+func (s *CheckerSuite) TestCheckCalls_GenericMethodsMatchLiterally() {
+	// given
+	cfg := &config.Config{
+		Version: 1,
+		Groups: map[string]*config.Group{
+			"domain": {
+				Paths:          config.PathConfigs{{Dir: "internal/domain/**"}},
+				ForbiddenCalls: []string{"(*internal/list.List[T]).Push", "internal/list.Map[K,V]"},
+			},
+		},
+	}
+	manager, err := groups.NewGroupManager(cfg)
+	s.Require().NoError(err)
+
+	calls := []analysis.Call{
+		{Package: "internal/domain/user", Name: "(*internal/list.List[T]).Push"},
+		{Package: "internal/domain/user", Name: "(*internal/list.ListT).Push"},
+		{Package: "internal/domain/user", Name: "(*internal/list.List).Push"},
+		{Package: "internal/domain/user", Name: "internal/list.Map[K,V]"},
+		{Package: "internal/domain/user", Name: "internal/list.MapK"},
+	}
+
+	// when
+	violations, err := CheckCalls(context.Background(), calls, manager)
+
+	// then - brackets are not character classes
+	assert.NoError(s.T(), err)
+	s.Require().Len(violations, 2)
+	assert.Equal(s.T(), "(*internal/list.List[T]).Push", violations[0].Target())
+	assert.Equal(s.T(), "internal/list.Map[K,V]", violations[1].Target())
+}
+
+func (s *CheckerSuite) TestCheckExports_DenyAndAllow() {
+	// given
+	cfg := &config.Config{
+		Version: 1,
+		Groups: map[string]*config.Group{
+			"infrastructure": {
+				Paths: config.PathConfigs{{Dir: "internal/store/**"}},
+			},
+			"domain": {
+				Paths: config.PathConfigs{{Dir: "internal/domain/**"}},
+				Exports: &config.Dependencies{
+					Deny: &config.DependencyRule{
+						Groups:  []string{"infrastructure"},
+						Message: "Keep storage types out of the domain API",
+					},
+					Allow: &config.DependencyRule{
+						Patterns: []string{"context", "time"},
+					},
+				},
+			},
+		},
+	}
+	manager, err := groups.NewGroupManager(cfg)
+	s.Require().NoError(err)
+
+	ref := func(decl, typePackage, typeName string) analysis.TypeRef {
+		return analysis.TypeRef{Package: "internal/domain/user", Decl: decl, TypePackage: typePackage, TypeName: typeName}
+	}
+	refs := []analysis.TypeRef{
+		ref("New", "internal/store/sql", "DB"),
+		ref("User.Save", "context", "Context"),
+		ref("User.Created", "time", "Time"),
+		ref("User.Address", "internal/domain/address", "Address"),
+		ref("User.Raw", "encoding/json", "RawMessage"),
+	}
+
+	// when
+	violations, err := CheckExports(context.Background(), refs, manager)
+
+	// then
+	assert.NoError(s.T(), err)
+	s.Require().Len(violations, 2)
+	assert.Equal(s.T(), "internal/store/sql.DB", violations[0].Target())
+	assert.Equal(s.T(), `exports deny group "infrastructure"`, violations[0].Rule)
+	assert.Equal(s.T(), "Keep storage types out of the domain API", violations[0].Message)
+	assert.Equal(s.T(), "User.Raw", violations[1].Decl)
+	assert.Equal(s.T(), "exports not allowed", violations[1].Rule)
+}
+
+func (s *CheckerSuite) TestCheckTransitive_ReportsShortestChain() {
+	// given
+	cfg := &config.Config{
+		Version: 1,
+		Groups: map[string]*config.Group{
+			"domain": {
+				Paths: config.PathConfigs{{Dir: "internal/domain/**"}},
+				Dependencies: &config.Dependencies{
+					Deny: &config.DependencyRule{
+						Patterns:   []string{"database/sql", "internal/infra/**"},
+						Transitive: true,
+					},
+				},
+			},
+		},
+	}
+	manager, err := groups.NewGroupManager(cfg)
+	s.Require().NoError(err)
+
+	graph := map[string]map[string]struct{}{
+		"internal/domain/user": {
+			"github.com/example/app/internal/shared/db":  {},
+			"github.com/example/app/internal/shared/log": {},
+			"github.com/example/app/internal/infra/mail": {},
+		},
+		"internal/shared/db": {
+			"database/sql": {},
+			"github.com/example/app/internal/shared/log": {},
+		},
+		"internal/shared/log": {
+			"github.com/example/app/internal/infra/logsink": {},
+		},
+	}
+	packages := map[string]map[string]struct{}{
+		"internal/domain/user": graph["internal/domain/user"],
+	}
+
+	// when
+	violations, err := CheckTransitive(context.Background(), "github.com/example/app", packages, graph, manager)
+
+	// then
+	assert.NoError(s.T(), err)
+	chains := map[string]string{}
+	for _, v := range violations {
+		chains[v.Import] = v.Chain
+	}
+	assert.Equal(s.T(), map[string]string{
+		"database/sql":           "internal/domain/user -> internal/shared/db -> database/sql",
+		"internal/infra/logsink": "internal/domain/user -> internal/shared/log -> internal/infra/logsink",
+	}, chains)
+}
+
+func (s *CheckerSuite) TestCheckLimits_ReportsCountsAndTopOffenders() {
+	// given
+	cfg := &config.Config{
+		Version: 1,
+		Groups: map[string]*config.Group{
+			"service": {
+				Paths:        config.PathConfigs{{Dir: "internal/service/**"}},
+				MaxImports:   2,
+				MaxImporters: 2,
+			},
+			"shared": {
+				Paths:        config.PathConfigs{{Dir: "internal/shared/**"}},
+				MaxImporters: 2,
+			},
+		},
+	}
+	manager, err := groups.NewGroupManager(cfg)
+	s.Require().NoError(err)
+
+	graph := map[string]map[string]struct{}{
+		"internal/service/user": {
+			"github.com/example/app/internal/service/order": {},
+			"github.com/example/app/internal/shared/db":     {},
+			"github.com/example/app/internal/shared/log":    {},
+			"fmt": {},
+		},
+		"internal/service/order": {
+			"github.com/example/app/internal/shared/log": {},
+		},
+		"internal/shared/db": {
+			"github.com/example/app/internal/shared/log": {},
+		},
+		"internal/shared/log": {},
+		"cmd/app": {
+			"github.com/example/app/internal/service/user": {},
+		},
+	}
+
+	// when
+	violations, err := CheckLimits(context.Background(), "github.com/example/app", graph, graph, manager)
+
+	// then
+	assert.NoError(s.T(), err)
+	s.Require().Len(violations, 2)
+	byRule := map[string]Violation{}
+	for _, v := range violations {
+		byRule[v.GroupName+" "+v.Rule] = v
+	}
+
+	fanOut := byRule["service maxImports 2"]
+	assert.Equal(s.T(), 3, fanOut.Count)
+	assert.Equal(s.T(), "internal/service/user", fanOut.Package)
+	assert.Equal(s.T(), "internal/service/user (3), internal/service/order (1)", fanOut.Offenders)
+
+	fanIn := byRule["shared maxImporters 2"]
+	assert.Equal(s.T(), 3, fanIn.Count)
+	assert.Equal(s.T(), "internal/shared/log (3), internal/shared/db (1)", fanIn.Offenders)
+}
+
+func (s *CheckerSuite) TestCheckLimits_OnlyGroupsWithCheckedPackages() {
+	// given
+	cfg := &config.Config{
+		Version: 1,
+		Groups: map[string]*config.Group{
+			"service": {
+				Paths:      config.PathConfigs{{Dir: "internal/service/**"}},
+				MaxImports: 1,
+			},
+			"shared": {
+				Paths:        config.PathConfigs{{Dir: "internal/shared/**"}},
+				MaxImporters: 1,
+			},
+		},
+	}
+	manager, err := groups.NewGroupManager(cfg)
+	s.Require().NoError(err)
+
+	graph := map[string]map[string]struct{}{
+		"internal/service/user": {
+			"github.com/example/app/internal/shared/db":  {},
+			"github.com/example/app/internal/shared/log": {},
+		},
+		"internal/shared/db":  {},
+		"internal/shared/log": {},
+		"cmd/app": {
+			"github.com/example/app/internal/shared/log": {},
+		},
+	}
+	packages := map[string]map[string]struct{}{
+		"internal/service/user": graph["internal/service/user"],
+	}
+
+	// when
+	violations, err := CheckLimits(context.Background(), "github.com/example/app", packages, graph, manager)
+
+	// then - shared is over its limit too, but none of its packages is checked
+	assert.NoError(s.T(), err)
+	s.Require().Len(violations, 1)
+	assert.Equal(s.T(), "service", violations[0].GroupName)
+	assert.Equal(s.T(), 2, violations[0].Count)
+}
+
+func (s *CheckerSuite) TestPrivateGroup_OnlyGroupAndFriendsMayImport() {
+	// given
+	cfg := &config.Config{
+		Version: 1,
+		Groups: map[string]*config.Group{
+			"billing": {
+				Paths:   config.PathConfigs{{Dir: "internal/billing/**"}},
+				Private: true,
+				Friends: []string{"api"},
+			},
+			"api": {
+				Paths: config.PathConfigs{{Dir: "internal/api/**"}},
+			},
+			"service": {
+				Paths: config.PathConfigs{{Dir: "internal/service/**"}},
+			},
+		},
+	}
+	manager, err := groups.NewGroupManager(cfg)
+	s.Require().NoError(err)
+
+	packages := map[string]map[string]struct{}{
+		"internal/billing/invoice": {
+			"github.com/example/app/internal/billing/tax": {},
+		},
+		"internal/api/billing": {
+			"github.com/example/app/internal/billing/invoice": {},
+		},
+		"internal/service/order": {
+			"github.com/example/app/internal/billing/invoice": {},
+		},
+		"cmd/tool": {
+			"github.com/example/app/internal/billing/tax": {},
+		},
+	}
+
+	// when
+	result, err := Check(context.Background(), "github.com/example/app", packages, manager)
+	s.Require().NoError(err)
+	violations, err := CheckPrivate(context.Background(), "github.com/example/app", packages, manager)
+
+	// then - group rules do not restrict importers
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), result.Violations)
+	violating := map[string]string{}
+	for _, v := range violations {
+		assert.Equal(s.T(), KindPrivate, v.Kind)
+		assert.Equal(s.T(), "billing", v.GroupName)
+		assert.Equal(s.T(), `private, friends "api"`, v.Rule)
+		violating[v.Package] = v.Import
+	}
+	assert.Equal(s.T(), map[string]string{
+		"internal/service/order": "internal/billing/invoice",
+		"cmd/tool":               "internal/billing/tax",
+	}, violating)
+}
+
+func (s *CheckerSuite) TestCheckImportForms_ForbiddenForms() {
+	// given
+	cfg := &config.Config{
+		Version: 1,
+		Groups: map[string]*config.Group{
+			"domain": {
+				Paths:                config.PathConfigs{{Dir: "internal/domain/**"}},
+				ForbiddenImportForms: []config.ImportForm{config.ImportFormDot, config.ImportFormBlank, config.ImportFormCgo},
+			},
+		},
+	}
+	manager, err := groups.NewGroupManager(cfg)
+	s.Require().NoError(err)
+
+	files := map[string][]loader.Import{
+		"internal/domain/user/user.go": {
+			{Path: "fmt", Line: 3},
+			{Path: "github.com/example/app/internal/domain/shared", Name: ".", Line: 4},
+			{Path: "github.com/lib/pq", Name: "_", Line: 5},
+			{Path: "C", Line: 6},
+			{Path: "strings", Name: "str", Line: 7},
+		},
+		"cmd/app/main.go": {
+			{Path: "github.com/lib/pq", Name: "_", Line: 3},
+		},
+	}
+	packages := map[string]map[string]struct{}{
+		"internal/domain/user": {},
+		"cmd/app":              {},
+	}
+
+	// when
+	violations, err := CheckImportForms(context.Background(), "github.com/example/app", files, packages, manager)
+
+	// then
+	assert.NoError(s.T(), err)
+	var targets []string
+	for _, v := range violations {
+		assert.Equal(s.T(), "internal/domain/user/user.go", v.Pos.Filename)
+		targets = append(targets, v.Target())
+	}
+	assert.Equal(s.T(), []string{"internal/domain/shared (dot)", "github.com/lib/pq (blank)", "C (cgo)"}, targets)
+}
+
+func (s *CheckerSuite) TestCheckAliases_RequiredAndForbiddenNames() {
+	// given
+	aliases := map[string]*config.Alias{
+		"k8s.io/api/core/v1":         {Name: "corev1"},
+		"github.com/sirupsen/logrus": {Forbidden: []string{"log"}},
+		"internal/domain/*":          {Forbidden: []string{"d"}, Severity: config.SeverityWarning},
+	}
+	files := map[string][]loader.Import{
+		"internal/api/user.go": {
+			{Path: "k8s.io/api/core/v1", Line: 4},
+			{Path: "github.com/sirupsen/logrus", Name: "log", Line: 5},
+			{Path: "github.com/example/app/internal/domain/user", Name: "d", Line: 6},
+			{Path: "github.com/lib/pq", Name: "_", Line: 7},
+		},
+		"internal/api/order.go": {
+			{Path: "k8s.io/api/core/v1", Name: "corev1", Line: 4},
+		},
+	}
+	packages := map[string]map[string]struct{}{"internal/api": {}}
+	packageName := func(importPath string) string { return path.Base(importPath) }
+
+	// when
+	violations, err := CheckAliases("github.com/example/app", files, packages, aliases, packageName)
+
+	// then
+	assert.NoError(s.T(), err)
+	s.Require().Len(violations, 3)
+	assert.Equal(s.T(), "k8s.io/api/core/v1 as v1", violations[0].Target())
+	assert.Equal(s.T(), `alias "corev1" required`, violations[0].Rule)
+	assert.Equal(s.T(), "corev1", violations[0].WantAlias)
+	assert.Equal(s.T(), "aliases", violations[0].DeniedBy())
+	assert.Equal(s.T(), "github.com/sirupsen/logrus as log", violations[1].Target())
+	assert.Equal(s.T(), "internal/domain/user as d", violations[2].Target())
+	assert.Equal(s.T(), `alias "d" forbidden`, violations[2].Rule)
+	assert.Equal(s.T(), config.SeverityWarning, violations[2].Severity)
+}
+
+func (s *CheckerSuite) TestCheckAliases_PartlyOverlappingRequiredNamesRejected() {
+	// given
+	aliases := map[string]*config.Alias{
+		"k8s.io/*/v1":  {Name: "v1"},
+		"k8s.io/api/*": {Name: "k8sapi"},
+	}
+	files := map[string][]loader.Import{
+		"internal/api/user.go": {
+			{Path: "k8s.io/api/v1", Line: 4},
+		},
+	}
+	packages := map[string]map[string]struct{}{"internal/api": {}}
+	packageName := func(importPath string) string { return path.Base(importPath) }
+
+	// when
+	_, err := CheckAliases("github.com/example/app", files, packages, aliases, packageName)
+
+	// then
+	assert.ErrorContains(s.T(), err, `aliases k8s.io/*/v1 and k8s.io/api/* both match k8s.io/api/v1 but require different names "v1" and "k8sapi"`)
+}
+
+func (s *CheckerSuite) TestCheckUnsafe_OnlyDesignatedGroups() {
+	// given
+	cfg := &config.Config{
+		Version: 1,
+		Groups: map[string]*config.Group{
+			"runtime": {
+				Paths:       config.PathConfigs{{Dir: "internal/runtime/**"}},
+				AllowUnsafe: []config.UnsafeFeature{config.UnsafeLinkname},
+			},
+			"domain": {
+				Paths: config.PathConfigs{{Dir: "internal/domain/**"}},
+			},
+		},
+	}
+	manager, err := groups.NewGroupManager(cfg)
+	s.Require().NoError(err)
+
+	uses := []loader.UnsafeUse{
+		{Package: "internal/runtime/clock", Directive: "//go:linkname nanotime runtime.nanotime"},
+		{Package: "internal/domain/user", Directive: "//go:linkname fastrand runtime.fastrand"},
+		{Package: "internal/domain/user", Name: "Pointer"},
+	}
+
+	// when
+	violations, err := CheckUnsafe(context.Background(), uses, manager)
+
+	// then
+	assert.NoError(s.T(), err)
+	s.Require().Len(violations, 1)
+	assert.Equal(s.T(), "internal/domain/user", violations[0].Package)
+	assert.Equal(s.T(), "//go:linkname fastrand runtime.fastrand", violations[0].Target())
+	assert.Equal(s.T(), "linkname not allowed", violations[0].Rule)
+}
+
+func (s *CheckerSuite) TestCheckUnsafe_PackagesWithoutGroup() {
+	// given
+	cfg := &config.Config{
+		Version: 1,
+		Groups: map[string]*config.Group{
+			"runtime": {
+				Paths:       config.PathConfigs{{Dir: "internal/runtime/**"}},
+				AllowUnsafe: []config.UnsafeFeature{config.UnsafePackage},
+			},
+		},
+	}
+	manager, err := groups.NewGroupManager(cfg)
+	s.Require().NoError(err)
+
+	uses := []loader.UnsafeUse{
+		{Package: "internal/runtime/clock", Name: "Pointer"},
+		{Package: "tools/gen", Name: "Sizeof"},
+		{Package: "tools/gen", Directive: "//go:linkname nanotime runtime.nanotime"},
+	}
+
+	// when
+	violations, err := CheckUnsafe(context.Background(), uses, manager)
+
+	// then
+	assert.NoError(s.T(), err)
+	s.Require().Len(violations, 1)
+	assert.Equal(s.T(), "tools/gen", violations[0].Package)
+	assert.Equal(s.T(), "unsafe.Sizeof", violations[0].Target())
+	assert.Empty(s.T(), violations[0].GroupName)
+	assert.Equal(s.T(), "allowUnsafe", violations[0].DeniedBy())
+	assert.Equal(s.T(), "unsafe not allowed", violations[0].Rule)
+	assert.Equal(s.T(), config.SeverityError, violations[0].Severity)
+}
+
+func (s *CheckerSuite) TestViolation_TargetAndDeniedByOfEachKind() {
+	// given
+	violations := []Violation{
+		{Kind: KindImport, GroupName: "domain", Import: "internal/infra"},
+		{Kind: KindPrivate, GroupName: "billing", Import: "internal/billing/invoice"},
+		{Kind: KindTransitive, GroupName: "domain", Import: "database/sql", Chain: "internal/infra -> database/sql"},
+		{Kind: KindSymbol, GroupName: "domain", Import: "internal/legacy", Symbol: "DB"},
+		{Kind: KindCall, GroupName: "domain", Call: "os.Exit"},
+		{Kind: KindExport, GroupName: "api", Import: "internal/store", Symbol: "Row", Decl: "Handler"},
+		{Kind: KindImportForm, GroupName: "domain", Import: "internal/shared", Form: "dot"},
+		{Kind: KindAlias, Import: "k8s.io/api/core/v1", Alias: "v1", WantAlias: "corev1"},
+		{Kind: KindLinkname, GroupName: "domain", Directive: "//go:linkname nanotime runtime.nanotime"},
+		{Kind: KindUnsafe, Import: "unsafe", Symbol: "Pointer"},
+		{Kind: KindLimit, GroupName: "domain", Count: 12, Offenders: "internal/domain/user (7)"},
+	}
+
+	// when
+	var targets, deniedBy []string
+	for _, v := range violations {
+		targets = append(targets, v.Target())
+		deniedBy = append(deniedBy, v.DeniedBy())
+	}
+
+	// then
+	assert.Equal(s.T(), []string{
+		"internal/infra",
+		"internal/billing/invoice",
+		"database/sql",
+		"internal/legacy.DB",
+		"os.Exit",
+		"internal/store.Row",
+		"internal/shared (dot)",
+		"k8s.io/api/core/v1 as v1",
+		"//go:linkname nanotime runtime.nanotime",
+		"unsafe.Pointer",
+		"12 packages",
+	}, targets)
+	assert.Equal(s.T(), []string{
+		`group "domain"`,
+		`group "billing"`,
+		`group "domain"`,
+		`group "domain"`,
+		`group "domain"`,
+		`group "api"`,
+		`group "domain"`,
+		"aliases",
+		`group "domain"`,
+		"allowUnsafe",
+		`group "domain"`,
+	}, deniedBy)
+}
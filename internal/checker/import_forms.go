@@ -0,0 +1,89 @@
+package checker
+
+import (
+	"context"
+	"go/token"
+	"path/filepath"
+	"sort"
+
+	"github.com/coderhyme/arch-lint/internal/config"
+	"github.com/coderhyme/arch-lint/internal/groups"
+	"github.com/coderhyme/arch-lint/internal/loader"
+)
+
+// CheckImportForms checks the import declarations of files against the forbidden import
+// forms of the groups of their packages. files holds the imports of each file keyed by its
+// path relative to the module root; only files of packageImports are checked.
+func CheckImportForms(ctx context.Context, modulePath string, files map[string][]loader.Import, packageImports map[string]map[string]struct{}, manager groups.GroupManager) ([]Violation, error) {
+	paths := make([]string, 0, len(files))
+	for relPath := range files {
+		paths = append(paths, relPath)
+	}
+	sort.Strings(paths)
+
+	var violations []Violation
+	for _, relPath := range paths {
+		pkgPath := filepath.Dir(relPath)
+		if _, ok := packageImports[pkgPath]; !ok {
+			continue
+		}
+
+		matchingGroups, err := manager.GetGroups(ctx, pkgPath)
+		if err != nil {
+			return nil, err
+		}
+		if len(matchingGroups) == 0 {
+			continue
+		}
+
+		for _, imp := range files[relPath] {
+			importPath := imp.Path
+			if relImport, ok := StripModulePrefix(modulePath, imp.Path); ok {
+				importPath = relImport
+			}
+
+			for _, form := range importForms(imp) {
+				for _, grp := range matchingGroups {
+					checker := grp.GetDependencyChecker(pkgPath)
+					if decision := checker.DecideImportForm(form); !decision.Allowed {
+						violations = append(violations, Violation{
+							Kind:      KindImportForm,
+							Package:   pkgPath,
+							Import:    importPath,
+							GroupName: grp.Name(),
+							Rule:      decision.Rule,
+							Severity:  decision.Severity,
+							Source:    grp.Source(),
+							Form:      string(form),
+							Pos:       token.Position{Filename: filepath.ToSlash(relPath), Line: imp.Line},
+						})
+					}
+				}
+			}
+		}
+	}
+	return violations, nil
+}
+
+// importForms returns the forms an import is written in: at most one of dot, blank and
+// aliased, and cgo or unsafe for the pseudo-package "C" and the package unsafe.
+func importForms(imp loader.Import) []config.ImportForm {
+	var forms []config.ImportForm
+	switch imp.Name {
+	case "":
+	case ".":
+		forms = append(forms, config.ImportFormDot)
+	case "_":
+		forms = append(forms, config.ImportFormBlank)
+	default:
+		forms = append(forms, config.ImportFormAliased)
+	}
+
+	switch imp.Path {
+	case "C":
+		forms = append(forms, config.ImportFormCgo)
+	case "unsafe":
+		forms = append(forms, config.ImportFormUnsafe)
+	}
+	return forms
+}
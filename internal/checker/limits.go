@@ -0,0 +1,133 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/coderhyme/arch-lint/internal/groups"
+)
+
+// maxOffenders is the number of packages listed for a group that exceeds a limit.
+const maxOffenders = 3
+
+// CheckLimits checks the fan-out and fan-in of every group with limits that has a package in
+// packages. graph holds the imports of every package of the module, and the counts cover all
+// of it. Fan-out counts the distinct module packages the group's packages import and fan-in
+// the distinct module packages importing them, whether they belong to the same group or not.
+// A group over a limit is reported once, on the package contributing the most, with the
+// other top offenders listed.
+func CheckLimits(ctx context.Context, modulePath string, packages, graph map[string]map[string]struct{}, manager groups.GroupManager) ([]Violation, error) {
+	allGroups, err := manager.ListGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// importers holds the module packages importing each module package.
+	importers := make(map[string]map[string]struct{})
+	for pkgPath, imports := range graph {
+		for imp := range imports {
+			rel, internal := StripModulePrefix(modulePath, imp)
+			if !internal || rel == pkgPath {
+				continue
+			}
+			if importers[rel] == nil {
+				importers[rel] = make(map[string]struct{})
+			}
+			importers[rel][pkgPath] = struct{}{}
+		}
+	}
+
+	var violations []Violation
+	for _, grp := range allGroups {
+		limits := grp.Limits()
+		if limits.MaxImports == 0 && limits.MaxImporters == 0 {
+			continue
+		}
+
+		var members []string
+		checked := false
+		for pkgPath := range graph {
+			if grp.MatchPath(pkgPath) {
+				members = append(members, pkgPath)
+				_, inPackages := packages[pkgPath]
+				checked = checked || inPackages
+			}
+		}
+		if !checked {
+			continue
+		}
+
+		if limits.MaxImports > 0 {
+			counted := make(map[string]struct{})
+			perPackage := make(map[string]int)
+			for _, pkgPath := range members {
+				for imp := range graph[pkgPath] {
+					rel, internal := StripModulePrefix(modulePath, imp)
+					if !internal || rel == pkgPath {
+						continue
+					}
+					counted[rel] = struct{}{}
+					perPackage[pkgPath]++
+				}
+			}
+			if len(counted) > limits.MaxImports {
+				violations = append(violations, limitViolation(grp, "maxImports", limits.MaxImports, len(counted), perPackage))
+			}
+		}
+
+		if limits.MaxImporters > 0 {
+			counted := make(map[string]struct{})
+			perPackage := make(map[string]int)
+			for _, pkgPath := range members {
+				for importer := range importers[pkgPath] {
+					counted[importer] = struct{}{}
+				}
+				perPackage[pkgPath] = len(importers[pkgPath])
+			}
+			if len(counted) > limits.MaxImporters {
+				violations = append(violations, limitViolation(grp, "maxImporters", limits.MaxImporters, len(counted), perPackage))
+			}
+		}
+	}
+	return violations, nil
+}
+
+func limitViolation(grp groups.Group, name string, limit, count int, perPackage map[string]int) Violation {
+	packages := make([]string, 0, len(perPackage))
+	for pkgPath, n := range perPackage {
+		if n > 0 {
+			packages = append(packages, pkgPath)
+		}
+	}
+	sort.Slice(packages, func(i, j int) bool {
+		if perPackage[packages[i]] != perPackage[packages[j]] {
+			return perPackage[packages[i]] > perPackage[packages[j]]
+		}
+		return packages[i] < packages[j]
+	})
+	if len(packages) > maxOffenders {
+		packages = packages[:maxOffenders]
+	}
+
+	offenders := make([]string, 0, len(packages))
+	for _, pkgPath := range packages {
+		offenders = append(offenders, fmt.Sprintf("%s (%d)", pkgPath, perPackage[pkgPath]))
+	}
+
+	var pkg string
+	if len(packages) > 0 {
+		pkg = packages[0]
+	}
+	return Violation{
+		Kind:      KindLimit,
+		Package:   pkg,
+		GroupName: grp.Name(),
+		Rule:      fmt.Sprintf("%s %d", name, limit),
+		Severity:  grp.Limits().Severity,
+		Source:    grp.Source(),
+		Count:     count,
+		Offenders: strings.Join(offenders, ", "),
+	}
+}
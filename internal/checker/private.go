@@ -0,0 +1,40 @@
+package checker
+
+import (
+	"context"
+
+	"github.com/coderhyme/arch-lint/internal/groups"
+)
+
+// CheckPrivate checks the module imports of packageImports against the private groups of
+// the imported packages. Importers are checked whether or not they belong to a group.
+func CheckPrivate(ctx context.Context, modulePath string, packageImports map[string]map[string]struct{}, manager groups.GroupManager) ([]Violation, error) {
+	var violations []Violation
+	for pkgPath, imports := range packageImports {
+		for imp := range imports {
+			relImport, ok := StripModulePrefix(modulePath, imp)
+			if !ok {
+				continue
+			}
+
+			importedGroups, err := manager.GetGroups(ctx, relImport)
+			if err != nil {
+				return nil, err
+			}
+			for _, grp := range importedGroups {
+				if decision := grp.DecideImporter(pkgPath); !decision.Allowed {
+					violations = append(violations, Violation{
+						Kind:      KindPrivate,
+						Package:   pkgPath,
+						Import:    relImport,
+						GroupName: grp.Name(),
+						Rule:      decision.Rule,
+						Severity:  decision.Severity,
+						Source:    grp.Source(),
+					})
+				}
+			}
+		}
+	}
+	return violations, nil
+}
@@ -0,0 +1,107 @@
+package checker
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/coderhyme/arch-lint/internal/groups"
+)
+
+// CheckTransitive checks the packages each package reaches through chains of imports
+// against transitive deny rules. graph holds the imports of every package of the module,
+// so chains are followed through packages that are not checked themselves. Packages
+// outside the module end a chain and are matched by their full import path. Direct imports
+// of module packages are left to Check. Each reached package is reported once per group,
+// with the shortest chain leading to it.
+func CheckTransitive(ctx context.Context, modulePath string, packageImports, graph map[string]map[string]struct{}, manager groups.GroupManager) ([]Violation, error) {
+	var violations []Violation
+	for pkgPath := range packageImports {
+		matchingGroups, err := manager.GetGroups(ctx, pkgPath)
+		if err != nil {
+			return nil, err
+		}
+		if len(matchingGroups) == 0 {
+			continue
+		}
+
+		for _, reached := range reachable(modulePath, pkgPath, graph) {
+			if len(reached.chain) == 1 && reached.internal {
+				continue
+			}
+
+			target := reached.chain[len(reached.chain)-1]
+			for _, grp := range matchingGroups {
+				checker := grp.GetDependencyChecker(pkgPath)
+				if decision := checker.DecideTransitive(target); !decision.Allowed {
+					violations = append(violations, Violation{
+						Kind:      KindTransitive,
+						Package:   pkgPath,
+						Import:    target,
+						GroupName: grp.Name(),
+						Rule:      decision.Rule,
+						Severity:  decision.Severity,
+						Source:    grp.Source(),
+						Message:   decision.Message,
+						Docs:      decision.Docs,
+						Chain:     strings.Join(append([]string{pkgPath}, reached.chain...), " -> "),
+					})
+				}
+			}
+		}
+	}
+	return violations, nil
+}
+
+type reachedPackage struct {
+	// chain lists the packages from a direct import of the start package to the reached
+	// package, relative to the module root for module packages.
+	chain    []string
+	internal bool
+}
+
+// reachable walks graph breadth-first from pkgPath and returns every package it reaches
+// with the shortest import chain, in the order they were reached.
+func reachable(modulePath, pkgPath string, graph map[string]map[string]struct{}) []reachedPackage {
+	visited := map[string]bool{pkgPath: true}
+	var result []reachedPackage
+	queue := []reachedPackage{{}}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		from := pkgPath
+		if len(current.chain) > 0 {
+			if !current.internal {
+				continue
+			}
+			from = current.chain[len(current.chain)-1]
+		}
+
+		imports := make([]string, 0, len(graph[from]))
+		for imp := range graph[from] {
+			imports = append(imports, imp)
+		}
+		sort.Strings(imports)
+
+		for _, imp := range imports {
+			next, internal := StripModulePrefix(modulePath, imp)
+			if !internal {
+				next = imp
+			}
+			if visited[next] {
+				continue
+			}
+			visited[next] = true
+
+			chain := make([]string, len(current.chain)+1)
+			copy(chain, current.chain)
+			chain[len(current.chain)] = next
+			reached := reachedPackage{chain: chain, internal: internal}
+			result = append(result, reached)
+			queue = append(queue, reached)
+		}
+	}
+	return result
+}
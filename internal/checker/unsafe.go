@@ -0,0 +1,92 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/coderhyme/arch-lint/internal/config"
+	"github.com/coderhyme/arch-lint/internal/groups"
+	"github.com/coderhyme/arch-lint/internal/loader"
+)
+
+// CheckUnsafe checks //go:linkname directives and uses of package unsafe against the groups
+// of the using packages. A feature is only checked once some group is designated for it,
+// and is allowed when any group of the package is. Otherwise each group of the package
+// reports the use at its position, and a package without a group reports it once with the
+// default severity.
+func CheckUnsafe(ctx context.Context, uses []loader.UnsafeUse, manager groups.GroupManager) ([]Violation, error) {
+	allGroups, err := manager.ListGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	designated := make(map[config.UnsafeFeature]bool)
+	for _, grp := range allGroups {
+		for _, feature := range []config.UnsafeFeature{config.UnsafeLinkname, config.UnsafePackage} {
+			if grp.DecideUnsafe(feature).Allowed {
+				designated[feature] = true
+			}
+		}
+	}
+
+	var violations []Violation
+	for _, use := range uses {
+		feature := config.UnsafePackage
+		if use.Directive != "" {
+			feature = config.UnsafeLinkname
+		}
+		if !designated[feature] {
+			continue
+		}
+
+		matchingGroups, err := manager.GetGroups(ctx, use.Package)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(matchingGroups) == 0 {
+			violations = append(violations, unsafeViolation(use, groups.Decision{
+				Rule:     fmt.Sprintf("%s not allowed", feature),
+				Severity: config.SeverityError,
+			}, nil))
+			continue
+		}
+
+		var denials []Violation
+		allowed := false
+		for _, grp := range matchingGroups {
+			decision := grp.DecideUnsafe(feature)
+			if decision.Allowed {
+				allowed = true
+				break
+			}
+			denials = append(denials, unsafeViolation(use, decision, grp))
+		}
+		if !allowed {
+			violations = append(violations, denials...)
+		}
+	}
+	return violations, nil
+}
+
+// unsafeViolation reports use as denied by grp, or by the designated groups when grp is nil.
+func unsafeViolation(use loader.UnsafeUse, decision groups.Decision, grp groups.Group) Violation {
+	v := Violation{
+		Kind:     KindLinkname,
+		Package:  use.Package,
+		Rule:     decision.Rule,
+		Severity: decision.Severity,
+		Pos:      use.Pos,
+	}
+	if use.Directive != "" {
+		v.Directive = use.Directive
+	} else {
+		v.Kind = KindUnsafe
+		v.Import = "unsafe"
+		v.Symbol = use.Name
+	}
+	if grp != nil {
+		v.GroupName = grp.Name()
+		v.Source = grp.Source()
+	}
+	return v
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"fmt"
+	"go/token"
+	"sort"
+
+	"github.com/gobwas/glob"
+	"go.yaml.in/yaml/v4"
+)
+
+// Alias constrains the names files import a package by. In the config, aliases map import
+// path globs to either a required name or an object with a name and forbidden names.
+type Alias struct {
+	// Name is the name the package must be imported by.
+	Name string `yaml:"name,omitempty"`
+	// Forbidden are names the package must not be imported by.
+	Forbidden []string `yaml:"forbidden,omitempty"`
+	Severity  Severity `yaml:"severity,omitempty"`
+	// Source is the config file the alias was declared in.
+	Source string `yaml:"-"`
+}
+
+// UnmarshalYAML accepts the required name as a plain string.
+func (a *Alias) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		a.Name = value.Value
+		return nil
+	}
+
+	type plain Alias
+	var pa plain
+	if err := value.Decode(&pa); err != nil {
+		return fmt.Errorf("failed to decode alias: %w", err)
+	}
+	*a = Alias(pa)
+	return nil
+}
+
+func validateAliases(cfg *Config) error {
+	patterns := make([]string, 0, len(cfg.Aliases))
+	for pattern := range cfg.Aliases {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	for _, pattern := range patterns {
+		alias := cfg.Aliases[pattern]
+		if alias == nil || (alias.Name == "" && len(alias.Forbidden) == 0) {
+			return fmt.Errorf("alias %s must set a name or forbidden names", pattern)
+		}
+		if alias.Name != "" && (!token.IsIdentifier(alias.Name) || alias.Name == "_") {
+			return fmt.Errorf("alias %s: name %q is not a valid package name", pattern, alias.Name)
+		}
+		for _, name := range alias.Forbidden {
+			if name == alias.Name {
+				return fmt.Errorf("alias %s: name %q is both required and forbidden", pattern, name)
+			}
+		}
+		if err := validateSeverity("alias "+pattern, alias.Severity); err != nil {
+			return err
+		}
+	}
+	return validateAliasConflicts(cfg.Aliases, patterns)
+}
+
+// validateAliasConflicts rejects two patterns that require different names when one of them
+// matches the other, such as a path and a glob covering it. Globs that only partly overlap
+// are reported by the check when an import matches both.
+func validateAliasConflicts(aliases map[string]*Alias, patterns []string) error {
+	globs := make(map[string]glob.Glob, len(patterns))
+	for _, pattern := range patterns {
+		g, err := glob.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid alias pattern %q: %w", pattern, err)
+		}
+		globs[pattern] = g
+	}
+
+	for i, a := range patterns {
+		for _, b := range patterns[i+1:] {
+			nameA, nameB := aliases[a].Name, aliases[b].Name
+			if nameA == "" || nameB == "" || nameA == nameB {
+				continue
+			}
+			if globs[a].Match(b) || globs[b].Match(a) {
+				return fmt.Errorf("aliases %s and %s overlap but require different names %q and %q", a, b, nameA, nameB)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"go.yaml.in/yaml/v4"
+)
+
+// composer resolves extends and include references between config files.
+type composer struct {
+	// active holds the files currently being loaded, to detect cycles.
+	active map[string]bool
+}
+
+func newComposer() *composer {
+	return &composer{active: make(map[string]bool)}
+}
+
+func (c *composer) loadFile(path string) (*Config, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+	if c.active[abs] {
+		return nil, fmt.Errorf("config %s extends or includes itself", path)
+	}
+	c.active[abs] = true
+	defer delete(c.active, abs)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	return c.compose(path, filepath.Dir(path), data)
+}
+
+// compose parses data read from source and merges in the files it includes and extends.
+// Relative references are resolved against dir.
+func (c *composer) compose(source, dir string, data []byte) (*Config, error) {
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		if source != "" {
+			return nil, fmt.Errorf("failed to parse YAML in %s: %w", source, err)
+		}
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+
+	for _, grp := range cfg.Groups {
+		if grp != nil {
+			grp.Source = source
+		}
+	}
+	for i := range cfg.Exceptions {
+		cfg.Exceptions[i].Source = source
+	}
+	for _, alias := range cfg.Aliases {
+		if alias != nil {
+			alias.Source = source
+		}
+	}
+
+	if err := c.resolveIncludes(&cfg, dir); err != nil {
+		return nil, err
+	}
+
+	if len(cfg.Extends) == 0 {
+		return &cfg, nil
+	}
+
+	merged := &Config{}
+	for _, base := range cfg.Extends {
+		baseCfg, err := c.loadFile(filepath.Join(dir, base))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load extended config %s: %w", base, err)
+		}
+		merged = merge(merged, baseCfg)
+	}
+	return merge(merged, &cfg), nil
+}
+
+// resolveIncludes adds the groups of every included file to cfg. Included files split a
+// config across files, so they may only declare groups and further includes, and a group
+// may be declared only once.
+func (c *composer) resolveIncludes(cfg *Config, dir string) error {
+	for _, pattern := range cfg.Include {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return fmt.Errorf("invalid include pattern %s: %w", pattern, err)
+		}
+		if len(matches) == 0 {
+			return fmt.Errorf("include %s matches no files", pattern)
+		}
+		sort.Strings(matches)
+
+		for _, file := range matches {
+			included, err := c.loadFile(file)
+			if err != nil {
+				return fmt.Errorf("failed to load included config %s: %w", file, err)
+			}
+			if len(included.Extends) > 0 || included.Layers != nil || len(included.Exceptions) > 0 || len(included.Aliases) > 0 {
+				return fmt.Errorf("included config %s may only declare groups and includes", file)
+			}
+
+			if cfg.Groups == nil {
+				cfg.Groups = make(map[string]*Group)
+			}
+			for name, grp := range included.Groups {
+				if existing, exists := cfg.Groups[name]; exists {
+					return fmt.Errorf("group %s is declared in both %s and %s", name, existing.Source, grp.Source)
+				}
+				cfg.Groups[name] = grp
+			}
+		}
+	}
+	return nil
+}
+
+// merge overlays over onto base. Groups are replaced as a whole by name, the version and
+// layers of over win when set, and exceptions of both are kept.
+func merge(base, over *Config) *Config {
+	result := &Config{
+		Version: base.Version,
+		Groups:  make(map[string]*Group),
+		Layers:  base.Layers,
+	}
+	result.Exceptions = append(append(result.Exceptions, base.Exceptions...), over.Exceptions...)
+	if over.Version != 0 {
+		result.Version = over.Version
+	}
+	if over.Layers != nil {
+		result.Layers = over.Layers
+	}
+
+	for name, grp := range base.Groups {
+		result.Groups[name] = grp
+	}
+	for name, grp := range over.Groups {
+		result.Groups[name] = grp
+	}
+
+	if len(base.Aliases)+len(over.Aliases) > 0 {
+		result.Aliases = make(map[string]*Alias)
+		for pattern, alias := range base.Aliases {
+			result.Aliases[pattern] = alias
+		}
+		for pattern, alias := range over.Aliases {
+			result.Aliases[pattern] = alias
+		}
+	}
+
+	return result
+}
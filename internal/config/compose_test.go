@@ -0,0 +1,227 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ComposeSuite struct {
+	suite.Suite
+	dir string
+}
+
+func TestComposeSuite(t *testing.T) {
+	suite.Run(t, new(ComposeSuite))
+}
+
+func (s *ComposeSuite) SetupTest() {
+	s.dir = s.T().TempDir()
+}
+
+func (s *ComposeSuite) write(name, content string) string {
+	path := filepath.Join(s.dir, name)
+	s.Require().NoError(os.MkdirAll(filepath.Dir(path), 0o755))
+	s.Require().NoError(os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func (s *ComposeSuite) TestExtends_ChildOverridesBaseGroups() {
+	// given
+	s.write("standards/base.yaml", `
+version: 1
+groups:
+  shared:
+    paths: "shared/**"
+  domain:
+    paths: "domain/**"
+`)
+	path := s.write("service/.arch-lint.yaml", `
+extends:
+  - ../standards/base.yaml
+groups:
+  domain:
+    paths: "internal/domain/**"
+  api:
+    paths: "internal/api/**"
+`)
+
+	// when
+	cfg, err := Load(path)
+
+	// then
+	s.Require().NoError(err)
+	assert.Equal(s.T(), 1, cfg.Version)
+	assert.Len(s.T(), cfg.Groups, 3)
+	assert.Equal(s.T(), "internal/domain/**", cfg.Groups["domain"].Paths[0].Dir)
+	assert.Equal(s.T(), filepath.Join(s.dir, "standards/base.yaml"), cfg.Groups["shared"].Source)
+	assert.Equal(s.T(), path, cfg.Groups["api"].Source)
+}
+
+func (s *ComposeSuite) TestInclude_SplitsGroupsAcrossFiles() {
+	// given
+	s.write("arch/domain.yaml", `
+groups:
+  domain:
+    paths: "internal/domain/**"
+`)
+	s.write("arch/api.yaml", `
+groups:
+  api:
+    paths: "internal/api/**"
+`)
+	path := s.write(".arch-lint.yaml", `
+version: 1
+include:
+  - arch/*.yaml
+`)
+
+	// when
+	cfg, err := Load(path)
+
+	// then
+	s.Require().NoError(err)
+	assert.Len(s.T(), cfg.Groups, 2)
+	assert.Equal(s.T(), filepath.Join(s.dir, "arch/api.yaml"), cfg.Groups["api"].Source)
+}
+
+func (s *ComposeSuite) TestInclude_DuplicateGroupRejected() {
+	// given
+	s.write("arch/domain.yaml", `
+groups:
+  domain:
+    paths: "internal/domain/**"
+`)
+	path := s.write(".arch-lint.yaml", `
+version: 1
+include:
+  - arch/domain.yaml
+groups:
+  domain:
+    paths: "domain/**"
+`)
+
+	// when
+	_, err := Load(path)
+
+	// then
+	assert.ErrorContains(s.T(), err, "group domain is declared in both")
+}
+
+func (s *ComposeSuite) TestExtends_CycleRejected() {
+	// given
+	s.write("a.yaml", "version: 1\nextends: [b.yaml]\n")
+	path := s.write("b.yaml", "version: 1\nextends: [a.yaml]\n")
+
+	// when
+	_, err := Load(path)
+
+	// then
+	assert.ErrorContains(s.T(), err, "extends or includes itself")
+}
+
+func (s *ComposeSuite) TestLoadTree_NestedConfigScopedToDirectory() {
+	// given
+	path := s.write(".arch-lint.yaml", `
+version: 1
+groups:
+  shared:
+    paths: "shared/**"
+`)
+	s.write("teams/payments/.arch-lint.yaml", `
+groups:
+  domain:
+    paths: "domain/**"
+    dependencies:
+      allow:
+        groups: [shared, api]
+        patterns: ["util/**", "/pkg/log"]
+  api:
+    paths: "api/**"
+`)
+
+	// when
+	cfg, err := LoadTree(path, s.dir)
+
+	// then
+	s.Require().NoError(err)
+	s.Require().Contains(cfg.Groups, "teams/payments:domain")
+	domain := cfg.Groups["teams/payments:domain"]
+	assert.Equal(s.T(), "teams/payments/domain/**", domain.Paths[0].Dir)
+	assert.Equal(s.T(), []string{"shared", "teams/payments:api"}, domain.Dependencies.Allow.Groups)
+	assert.Equal(s.T(), []string{"teams/payments/util/**", "pkg/log"}, domain.Dependencies.Allow.Patterns)
+	assert.Equal(s.T(), "teams/payments/.arch-lint.yaml", domain.Source)
+}
+
+func (s *ComposeSuite) TestLoadTree_NestedPathOutsideDirectoryRejected() {
+	// given
+	path := s.write(".arch-lint.yaml", "version: 1\n")
+	s.write("teams/payments/.arch-lint.yaml", `
+groups:
+  escape:
+    paths: "../orders/**"
+`)
+
+	// when
+	_, err := LoadTree(path, s.dir)
+
+	// then
+	assert.ErrorContains(s.T(), err, "must stay inside teams/payments")
+}
+
+func (s *ComposeSuite) TestExceptions_InvalidExpiryRejected() {
+	// given
+	path := s.write(".arch-lint.yaml", `
+version: 1
+groups: {}
+exceptions:
+  - package: internal/api
+    import: internal/repository
+    expires: next quarter
+`)
+
+	// when
+	_, err := Load(path)
+
+	// then
+	assert.ErrorContains(s.T(), err, "invalid expiry date")
+}
+
+func (s *ComposeSuite) TestAliases_OverlappingRequiredNamesRejected() {
+	// given
+	path := s.write(".arch-lint.yaml", `
+version: 1
+groups: {}
+aliases:
+  k8s.io/api/*: k8sapi
+  k8s.io/api/core/v1: corev1
+`)
+
+	// when
+	_, err := Load(path)
+
+	// then
+	assert.ErrorContains(s.T(), err, `aliases k8s.io/api/* and k8s.io/api/core/v1 overlap but require different names "k8sapi" and "corev1"`)
+}
+
+func (s *ComposeSuite) TestAliases_OverlappingForbiddenNamesAccepted() {
+	// given
+	path := s.write(".arch-lint.yaml", `
+version: 1
+groups: {}
+aliases:
+  k8s.io/api/*:
+    forbidden: [v1]
+  k8s.io/api/core/v1: corev1
+`)
+
+	// when
+	cfg, err := Load(path)
+
+	// then
+	s.Require().NoError(err)
+	assert.Len(s.T(), cfg.Aliases, 2)
+}
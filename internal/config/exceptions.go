@@ -0,0 +1,52 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// ExpiryLayout is the date format of Exception.Expires.
+const ExpiryLayout = time.DateOnly
+
+// Exception allows a single package→import edge that the rules deny until it expires.
+// Package and Import are relative to the module root like the packages in violations.
+type Exception struct {
+	Package string `yaml:"package"`
+	Import  string `yaml:"import"`
+	Reason  string `yaml:"reason,omitempty"`
+	Owner   string `yaml:"owner,omitempty"`
+	// Expires is the last day the exception applies, e.g. "2025-06-30".
+	Expires string `yaml:"expires"`
+	// Source is the config file the exception was declared in.
+	Source string `yaml:"-"`
+}
+
+// Matches reports whether the exception covers the edge from pkg to imp.
+func (e Exception) Matches(pkg, imp string) bool {
+	return e.Package == pkg && e.Import == imp
+}
+
+// Expired reports whether the expiry day of the exception has passed at now. Expires
+// must be valid, which validate ensures for loaded configs.
+func (e Exception) Expired(now time.Time) bool {
+	day, err := time.ParseInLocation(ExpiryLayout, e.Expires, now.Location())
+	if err != nil {
+		return true
+	}
+	return !now.Before(day.AddDate(0, 0, 1))
+}
+
+func validateExceptions(cfg *Config) error {
+	for i, e := range cfg.Exceptions {
+		if e.Package == "" || e.Import == "" {
+			return fmt.Errorf("exceptions[%d] must set package and import", i)
+		}
+		if e.Expires == "" {
+			return fmt.Errorf("exception %s -> %s has no expiry date", e.Package, e.Import)
+		}
+		if _, err := time.Parse(ExpiryLayout, e.Expires); err != nil {
+			return fmt.Errorf("exception %s -> %s has invalid expiry date %q, want YYYY-MM-DD", e.Package, e.Import, e.Expires)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package config
+
+import "fmt"
+
+// ImportForm is a way of writing an import declaration that groups can forbid.
+type ImportForm string
+
+const (
+	// ImportFormDot is an import named ".", which merges the package into the file scope.
+	ImportFormDot ImportForm = "dot"
+	// ImportFormBlank is an import named "_", imported only for its side effects.
+	ImportFormBlank ImportForm = "blank"
+	// ImportFormAliased is an import given any other name.
+	ImportFormAliased ImportForm = "aliased"
+	// ImportFormCgo is the import of the pseudo-package "C".
+	ImportFormCgo ImportForm = "cgo"
+	// ImportFormUnsafe is the import of the package unsafe.
+	ImportFormUnsafe ImportForm = "unsafe"
+)
+
+func validateImportForms(where string, forms []ImportForm) error {
+	for _, form := range forms {
+		switch form {
+		case ImportFormDot, ImportFormBlank, ImportFormAliased, ImportFormCgo, ImportFormUnsafe:
+		default:
+			return fmt.Errorf("%s: invalid import form %q: must be %q, %q, %q, %q or %q", where, form,
+				ImportFormDot, ImportFormBlank, ImportFormAliased, ImportFormCgo, ImportFormUnsafe)
+		}
+	}
+	return nil
+}
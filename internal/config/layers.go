@@ -0,0 +1,134 @@
+package config
+
+import (
+	"fmt"
+
+	"go.yaml.in/yaml/v4"
+)
+
+type LayerMode string
+
+const (
+	// LayersRelaxed lets a layer depend on every layer below it.
+	LayersRelaxed LayerMode = "relaxed"
+	// LayersStrict lets a layer depend only on the layer directly below it.
+	LayersStrict LayerMode = "strict"
+)
+
+// Layers lists groups from the top layer down.
+type Layers struct {
+	Mode   LayerMode `yaml:"mode"`
+	Groups []string  `yaml:"groups"`
+}
+
+// LayerRule is derived from the top-level layers list; it is never read from YAML directly.
+type LayerRule struct {
+	Layer string
+	// Above lists the layers higher than Layer.
+	Above []string
+	// Skipped lists the lower layers Layer may not reach directly in strict mode.
+	Skipped []string
+}
+
+func (l *Layers) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.SequenceNode {
+		var groups []string
+		if err := value.Decode(&groups); err != nil {
+			return fmt.Errorf("failed to decode layers: %w", err)
+		}
+		*l = Layers{Mode: LayersRelaxed, Groups: groups}
+		return nil
+	}
+
+	if value.Kind == yaml.MappingNode {
+		type plain Layers
+		var pl plain
+		if err := value.Decode(&pl); err != nil {
+			return fmt.Errorf("failed to decode layers: %w", err)
+		}
+		*l = Layers(pl)
+		if l.Mode == "" {
+			l.Mode = LayersRelaxed
+		}
+		return nil
+	}
+
+	return fmt.Errorf("layers must be a list of groups or an object")
+}
+
+func validateLayers(cfg *Config) error {
+	if cfg.Layers == nil {
+		return nil
+	}
+
+	if cfg.Layers.Mode != LayersRelaxed && cfg.Layers.Mode != LayersStrict {
+		return fmt.Errorf("invalid layers mode %q: must be %q or %q", cfg.Layers.Mode, LayersStrict, LayersRelaxed)
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range cfg.Layers.Groups {
+		if _, exists := cfg.Groups[name]; !exists {
+			return fmt.Errorf("layer %s is not a defined group", name)
+		}
+		if seen[name] {
+			return fmt.Errorf("layer %s is listed more than once", name)
+		}
+		seen[name] = true
+	}
+
+	return nil
+}
+
+// expandLayers turns the layers list into dependency rules: importing a higher layer, or
+// skipping a layer in strict mode, is a layer violation. A layer with allow or deny rules of its
+// own may also import itself and the layers below it (only the next one in strict mode); a layer
+// without rules keeps importing anything else freely.
+func expandLayers(cfg *Config) {
+	if cfg.Layers == nil {
+		return
+	}
+
+	layers := cfg.Layers.Groups
+	for i, name := range layers {
+		allowed := []string{name}
+		rule := &LayerRule{Layer: name}
+		for j, other := range layers {
+			switch {
+			case j < i:
+				rule.Above = append(rule.Above, other)
+			case j == i+1 || (j > i && cfg.Layers.Mode == LayersRelaxed):
+				allowed = append(allowed, other)
+			case j > i:
+				rule.Skipped = append(rule.Skipped, other)
+			}
+		}
+
+		grp := cfg.Groups[name]
+		if grp.Dependencies == nil {
+			grp.Dependencies = &Dependencies{}
+		}
+		if grp.Dependencies.Allow == nil && grp.Dependencies.Deny != nil {
+			grp.Dependencies.Allow = &DependencyRule{}
+		}
+		if grp.Dependencies.Allow != nil {
+			grp.Dependencies.Allow.Groups = appendMissing(grp.Dependencies.Allow.Groups, allowed...)
+		}
+		grp.Dependencies.Layer = rule
+	}
+}
+
+func appendMissing(list []string, items ...string) []string {
+	for _, item := range items {
+		found := false
+		for _, existing := range list {
+			if existing == item {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,30 @@
+package config
+
+import "fmt"
+
+// MetricThresholds bound the coupling metrics of a group, which all range from 0 to 1.
+// Unset thresholds are not checked.
+type MetricThresholds struct {
+	MaxInstability  *float64 `yaml:"maxInstability,omitempty"`
+	MinAbstractness *float64 `yaml:"minAbstractness,omitempty"`
+	MaxDistance     *float64 `yaml:"maxDistance,omitempty"`
+}
+
+func validateMetricThresholds(where string, m *MetricThresholds) error {
+	if m == nil {
+		return nil
+	}
+	for _, t := range []struct {
+		name  string
+		value *float64
+	}{
+		{"maxInstability", m.MaxInstability},
+		{"minAbstractness", m.MinAbstractness},
+		{"maxDistance", m.MaxDistance},
+	} {
+		if t.value != nil && (*t.value < 0 || *t.value > 1) {
+			return fmt.Errorf("%s: metrics.%s must be between 0 and 1", where, t.name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package config
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const DefaultFileName = ".arch-lint.yaml"
+
+// LoadTree loads the config at path and every nested .arch-lint.yaml found below root.
+// A nested config is scoped to its directory: its group paths, rule patterns and symbols
+// are relative to that directory, and its groups are named "<dir>:<group>". Rule patterns
+// and symbols starting with "/" are relative to root instead. Group references in a nested config
+// resolve to its own groups first and then to the groups of the root config, which stays
+// authoritative because its rules still apply to every package it matches. Group sources
+// are reported relative to root.
+func LoadTree(path, root string) (*Config, error) {
+	c := newComposer()
+	cfg, err := c.loadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	rootConfig, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+	for _, grp := range cfg.Groups {
+		if grp != nil {
+			grp.Source = relativeSource(root, grp.Source)
+		}
+	}
+	for i := range cfg.Exceptions {
+		cfg.Exceptions[i].Source = relativeSource(root, cfg.Exceptions[i].Source)
+	}
+	for _, alias := range cfg.Aliases {
+		if alias != nil {
+			alias.Source = relativeSource(root, alias.Source)
+		}
+	}
+
+	var nested []string
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != root && (strings.HasPrefix(d.Name(), ".") || d.Name() == "vendor") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Name() != DefaultFileName || filepath.Dir(p) == root {
+			return nil
+		}
+		if abs, err := filepath.Abs(p); err == nil && abs == rootConfig {
+			return nil
+		}
+		nested = append(nested, p)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover nested configs: %w", err)
+	}
+
+	for _, file := range nested {
+		if err := c.addNested(cfg, root, file); err != nil {
+			return nil, err
+		}
+	}
+
+	return finalize(cfg)
+}
+
+func (c *composer) addNested(cfg *Config, root, file string) error {
+	nestedCfg, err := c.loadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to load nested config %s: %w", file, err)
+	}
+	if nestedCfg.Layers != nil {
+		return fmt.Errorf("nested config %s may not declare layers", file)
+	}
+	if len(nestedCfg.Exceptions) > 0 {
+		return fmt.Errorf("nested config %s may not declare exceptions", file)
+	}
+	if len(nestedCfg.Aliases) > 0 {
+		return fmt.Errorf("nested config %s may not declare aliases", file)
+	}
+
+	relDir, err := filepath.Rel(root, filepath.Dir(file))
+	if err != nil {
+		return fmt.Errorf("failed to get relative path for %s: %w", file, err)
+	}
+	relDir = filepath.ToSlash(relDir)
+
+	local := func(name string) string {
+		return relDir + ":" + name
+	}
+
+	if cfg.Groups == nil {
+		cfg.Groups = make(map[string]*Group)
+	}
+
+	for name, grp := range nestedCfg.Groups {
+		if grp == nil {
+			return fmt.Errorf("group %s in %s has no paths configured", name, file)
+		}
+
+		grp.Source = relativeSource(root, grp.Source)
+
+		for i, pc := range grp.Paths {
+			if strings.HasPrefix(pc.Dir, "/") || strings.HasPrefix(path.Clean(pc.Dir), "..") {
+				return fmt.Errorf("group %s in %s: path %q must stay inside %s", name, file, pc.Dir, relDir)
+			}
+			grp.Paths[i].Dir = relDir + "/" + pc.Dir
+		}
+
+		for i, ref := range grp.Friends {
+			if _, exists := nestedCfg.Groups[ref]; exists {
+				grp.Friends[i] = local(ref)
+			}
+		}
+
+		for _, rule := range grp.rules() {
+			messages := make(map[string]RuleMessage)
+			for _, patterns := range [][]string{rule.Patterns, rule.Symbols} {
+				for i, pattern := range patterns {
+					if strings.HasPrefix(pattern, "/") {
+						patterns[i] = strings.TrimPrefix(pattern, "/")
+					} else {
+						patterns[i] = relDir + "/" + pattern
+					}
+					if msg, exists := rule.PatternMessages[pattern]; exists {
+						messages[patterns[i]] = msg
+					}
+				}
+			}
+			if rule.PatternMessages != nil {
+				rule.PatternMessages = messages
+			}
+			for i, ref := range rule.Groups {
+				if _, exists := nestedCfg.Groups[ref]; exists {
+					rule.Groups[i] = local(ref)
+				}
+			}
+		}
+
+		cfg.Groups[local(name)] = grp
+	}
+
+	return nil
+}
+
+// relativeSource expresses a config file path relative to root where possible.
+func relativeSource(root, source string) string {
+	abs, err := filepath.Abs(source)
+	if err != nil {
+		return source
+	}
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return source
+	}
+	rel, err := filepath.Rel(absRoot, abs)
+	if err != nil {
+		return source
+	}
+	return filepath.ToSlash(rel)
+}
@@ -2,28 +2,17 @@ package config
 
 import (
 	"fmt"
-	"os"
-
-	"go.yaml.in/yaml/v4"
 )
 
+// Load reads the config at path together with the files it extends and includes.
+// Each group records the file it was declared in as its Source.
 func Load(path string) (*Config, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
-	}
-
-	var cfg Config
-	err = yaml.Unmarshal(data, &cfg)
+	cfg, err := newComposer().loadFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+		return nil, err
 	}
 
-	if err := validate(&cfg); err != nil {
-		return nil, fmt.Errorf("invalid config: %w", err)
-	}
-
-	return &cfg, nil
+	return finalize(cfg)
 }
 
 func validate(cfg *Config) error {
@@ -32,7 +21,7 @@ func validate(cfg *Config) error {
 	}
 
 	for name, grp := range cfg.Groups {
-		if len(grp.Paths) == 0 {
+		if grp == nil || len(grp.Paths) == 0 {
 			return fmt.Errorf("group %s has no paths configured", name)
 		}
 
@@ -41,21 +30,82 @@ func validate(cfg *Config) error {
 				return fmt.Errorf("group %s paths[%d] has empty directory", name, i)
 			}
 		}
+
+		if err := validateSeverity("group "+name, grp.Severity); err != nil {
+			return err
+		}
+		if len(grp.Friends) > 0 && !grp.Private {
+			return fmt.Errorf("group %s: friends require private: true", name)
+		}
+		if err := validateImportForms("group "+name, grp.ForbiddenImportForms); err != nil {
+			return err
+		}
+		if err := validateUnsafeFeatures("group "+name, grp.AllowUnsafe); err != nil {
+			return err
+		}
+		if grp.MaxImports < 0 || grp.MaxImporters < 0 {
+			return fmt.Errorf("group %s: maxImports and maxImporters must not be negative", name)
+		}
+		if err := validateMetricThresholds("group "+name, grp.Metrics); err != nil {
+			return err
+		}
+		if err := validateRuleSeverities("group "+name, grp.Dependencies); err != nil {
+			return err
+		}
+		if err := validateRuleSeverities("group "+name+" exports", grp.Exports); err != nil {
+			return err
+		}
+		for _, rule := range grp.rules() {
+			if rule.Transitive && (grp.Dependencies == nil || rule != grp.Dependencies.Deny) {
+				return fmt.Errorf("group %s: transitive is only supported on dependencies.deny", name)
+			}
+		}
+	}
+
+	if err := validateExceptions(cfg); err != nil {
+		return err
 	}
+	if err := validateAliases(cfg); err != nil {
+		return err
+	}
+
+	return validateLayers(cfg)
+}
 
+func validateRuleSeverities(where string, deps *Dependencies) error {
+	if deps == nil {
+		return nil
+	}
+	if deps.Allow != nil {
+		if err := validateSeverity(where+" allow", deps.Allow.Severity); err != nil {
+			return err
+		}
+	}
+	if deps.Deny != nil {
+		if err := validateSeverity(where+" deny", deps.Deny.Severity); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// LoadFromBytes parses a config that is not backed by a file. References to extended and
+// included files are resolved against the working directory.
 func LoadFromBytes(data []byte) (*Config, error) {
-	var cfg Config
-	err := yaml.Unmarshal(data, &cfg)
+	cfg, err := newComposer().compose("", ".", data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+		return nil, err
 	}
 
-	if err := validate(&cfg); err != nil {
+	return finalize(cfg)
+}
+
+func finalize(cfg *Config) (*Config, error) {
+	if err := validate(cfg); err != nil {
 		return nil, fmt.Errorf("invalid config: %w", err)
 	}
 
-	return &cfg, nil
-}
\ No newline at end of file
+	expandLayers(cfg)
+
+	return cfg, nil
+}
@@ -0,0 +1,48 @@
+package config
+
+import "fmt"
+
+type Severity string
+
+const (
+	SeverityInfo    Severity = "info"
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+var severityRanks = map[Severity]int{
+	SeverityInfo:    1,
+	SeverityWarning: 2,
+	SeverityError:   3,
+}
+
+func ParseSeverity(s string) (Severity, error) {
+	sev := Severity(s)
+	if _, ok := severityRanks[sev]; !ok {
+		return "", fmt.Errorf("invalid severity %q: must be %q, %q or %q", s, SeverityInfo, SeverityWarning, SeverityError)
+	}
+	return sev, nil
+}
+
+// AtLeast reports whether s is as severe as threshold.
+func (s Severity) AtLeast(threshold Severity) bool {
+	return severityRanks[s] >= severityRanks[threshold]
+}
+
+// Or returns s, or fallback when s is not set.
+func (s Severity) Or(fallback Severity) Severity {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
+
+func validateSeverity(where string, s Severity) error {
+	if s == "" {
+		return nil
+	}
+	if _, err := ParseSeverity(string(s)); err != nil {
+		return fmt.Errorf("%s: %w", where, err)
+	}
+	return nil
+}
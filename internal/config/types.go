@@ -7,13 +7,151 @@ import (
 )
 
 type Config struct {
-	Version int              `yaml:"version"`
+	Version int               `yaml:"version"`
+	Extends []string          `yaml:"extends,omitempty"`
+	Include []string          `yaml:"include,omitempty"`
 	Groups  map[string]*Group `yaml:"groups"`
+	Layers  *Layers           `yaml:"layers,omitempty"`
+	// Exceptions are import edges that are allowed temporarily despite the rules.
+	Exceptions []Exception `yaml:"exceptions,omitempty"`
+	// Aliases constrain the names packages are imported by, keyed by import path glob.
+	Aliases map[string]*Alias `yaml:"aliases,omitempty"`
+}
+
+// HasTransitiveRules reports whether any group has transitive deny rules, which require
+// walking the package graph.
+func (c *Config) HasTransitiveRules() bool {
+	for _, grp := range c.Groups {
+		if grp != nil && grp.Dependencies != nil && grp.Dependencies.Deny != nil && grp.Dependencies.Deny.Transitive {
+			return true
+		}
+	}
+	return false
+}
+
+// HasLimits reports whether any group limits its fan-out or fan-in.
+func (c *Config) HasLimits() bool {
+	for _, grp := range c.Groups {
+		if grp != nil && (grp.MaxImports > 0 || grp.MaxImporters > 0) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPrivateGroups reports whether any group restricts its importers.
+func (c *Config) HasPrivateGroups() bool {
+	for _, grp := range c.Groups {
+		if grp != nil && grp.Private {
+			return true
+		}
+	}
+	return false
+}
+
+// HasImportFormRules reports whether any group forbids import forms.
+func (c *Config) HasImportFormRules() bool {
+	for _, grp := range c.Groups {
+		if grp != nil && len(grp.ForbiddenImportForms) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// HasUnsafeRules reports whether any group is designated to use //go:linkname directives or
+// package unsafe, which requires parsing whole files.
+func (c *Config) HasUnsafeRules() bool {
+	for _, grp := range c.Groups {
+		if grp != nil && len(grp.AllowUnsafe) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// HasSymbolRules reports whether any group has rules on the identifiers it uses, which
+// require parsing whole files.
+func (c *Config) HasSymbolRules() bool {
+	for _, grp := range c.Groups {
+		if grp == nil || grp.Dependencies == nil {
+			continue
+		}
+		for _, rule := range []*DependencyRule{grp.Dependencies.Allow, grp.Dependencies.Deny} {
+			if rule != nil && len(rule.Symbols) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HasCallRules reports whether any group forbids calls, which requires type-checking.
+func (c *Config) HasCallRules() bool {
+	for _, grp := range c.Groups {
+		if grp != nil && len(grp.ForbiddenCalls) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// HasExportRules reports whether any group restricts its exported API, which requires
+// type-checking.
+func (c *Config) HasExportRules() bool {
+	for _, grp := range c.Groups {
+		if grp != nil && grp.Exports != nil && (grp.Exports.Allow != nil || grp.Exports.Deny != nil) {
+			return true
+		}
+	}
+	return false
+}
+
+// rules returns the allow and deny rules of the group's dependencies and exports.
+func (g *Group) rules() []*DependencyRule {
+	var rules []*DependencyRule
+	for _, deps := range []*Dependencies{g.Dependencies, g.Exports} {
+		if deps == nil {
+			continue
+		}
+		for _, rule := range []*DependencyRule{deps.Allow, deps.Deny} {
+			if rule != nil {
+				rules = append(rules, rule)
+			}
+		}
+	}
+	return rules
 }
 
 type Group struct {
 	Paths        PathConfigs   `yaml:"paths"`
 	Dependencies *Dependencies `yaml:"dependencies,omitempty"`
+	// Exports restricts the packages whose types the group's exported API may reference,
+	// with allow and deny rules like Dependencies.
+	Exports *Dependencies `yaml:"exports,omitempty"`
+	// ForbiddenCalls are functions and methods the group's packages may not call, named like
+	// "os.Exit" or "(*log.Logger).Fatal*", and builtins such as "panic".
+	ForbiddenCalls []string `yaml:"forbiddenCalls,omitempty"`
+	// ForbiddenImportForms are the ways of importing packages the group may not use, such
+	// as dot, blank or cgo imports.
+	ForbiddenImportForms []ImportForm `yaml:"forbiddenImportForms,omitempty"`
+	// AllowUnsafe designates the group as one that may use //go:linkname directives or
+	// package unsafe. Once any group is designated for a feature, other groups may not use it.
+	AllowUnsafe []UnsafeFeature `yaml:"allowUnsafe,omitempty"`
+	// Private groups may only be imported by their own packages and the packages of the
+	// Friends groups.
+	Private bool     `yaml:"private,omitempty"`
+	Friends []string `yaml:"friends,omitempty"`
+	// MaxImports limits the number of module packages the group's packages import, and
+	// MaxImporters the number of module packages importing them. Zero means no limit.
+	MaxImports   int `yaml:"maxImports,omitempty"`
+	MaxImporters int `yaml:"maxImporters,omitempty"`
+	// Metrics are thresholds for the group's coupling metrics, checked by the metrics command.
+	Metrics *MetricThresholds `yaml:"metrics,omitempty"`
+	// Severity applies to violations of the group's rules that do not set their own.
+	Severity Severity `yaml:"severity,omitempty"`
+	// Source is the config file the group was declared in.
+	Source string `yaml:"-"`
 }
 
 type PathConfigs []PathConfig
@@ -72,11 +210,75 @@ func (p PathConfigs) AsStrings() []string {
 type Dependencies struct {
 	Allow *DependencyRule `yaml:"allow,omitempty"`
 	Deny  *DependencyRule `yaml:"deny,omitempty"`
+	Layer *LayerRule      `yaml:"-"`
 }
 
 type DependencyRule struct {
-	Relative    []string `yaml:"relative,omitempty"`
-	Groups      []string `yaml:"groups,omitempty"`
-	Patterns    []string `yaml:"patterns,omitempty"`
+	Relative []string `yaml:"relative,omitempty"`
+	Groups   []string `yaml:"groups,omitempty"`
+	Patterns []string `yaml:"patterns,omitempty"`
+	// Symbols match qualified identifiers such as "internal/domain.User". In deny rules they
+	// forbid using the identifiers; in allow rules they allow importing the package but
+	// restrict its use to the listed identifiers.
+	Symbols     []string `yaml:"symbols,omitempty"`
 	SubPackages bool     `yaml:"subPackages,omitempty"`
+	// Transitive makes a deny rule also match packages reached through other imports.
+	Transitive bool     `yaml:"transitive,omitempty"`
+	Severity   Severity `yaml:"severity,omitempty"`
+	// Message and Docs explain violations of the rule and point to what to do instead.
+	Message string `yaml:"message,omitempty"`
+	Docs    string `yaml:"docs,omitempty"`
+	// PatternMessages holds the message and docs given to individual patterns and symbols,
+	// keyed by pattern.
+	PatternMessages map[string]RuleMessage `yaml:"-"`
+}
+
+type RuleMessage struct {
+	Message string `yaml:"message,omitempty"`
+	Docs    string `yaml:"docs,omitempty"`
+}
+
+// UnmarshalYAML accepts patterns and symbols written either as strings or as objects with
+// a pattern, message and docs.
+func (r *DependencyRule) UnmarshalYAML(value *yaml.Node) error {
+	messages := make(map[string]RuleMessage)
+
+	if value.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(value.Content); i += 2 {
+			key := value.Content[i].Value
+			if (key != "patterns" && key != "symbols") || value.Content[i+1].Kind != yaml.SequenceNode {
+				continue
+			}
+
+			for j, node := range value.Content[i+1].Content {
+				if node.Kind != yaml.MappingNode {
+					continue
+				}
+
+				var pm struct {
+					Pattern     string `yaml:"pattern"`
+					RuleMessage `yaml:",inline"`
+				}
+				if err := node.Decode(&pm); err != nil {
+					return fmt.Errorf("failed to decode pattern: %w", err)
+				}
+				if pm.Pattern == "" {
+					return fmt.Errorf("pattern object has no pattern")
+				}
+				messages[pm.Pattern] = pm.RuleMessage
+				value.Content[i+1].Content[j] = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: pm.Pattern}
+			}
+		}
+	}
+
+	type plain DependencyRule
+	var pr plain
+	if err := value.Decode(&pr); err != nil {
+		return err
+	}
+	*r = DependencyRule(pr)
+	if len(messages) > 0 {
+		r.PatternMessages = messages
+	}
+	return nil
 }
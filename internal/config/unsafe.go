@@ -0,0 +1,23 @@
+package config
+
+import "fmt"
+
+// UnsafeFeature is a way of bypassing package boundaries that only designated groups may
+// use.
+type UnsafeFeature string
+
+const (
+	// UnsafeLinkname is a //go:linkname directive.
+	UnsafeLinkname UnsafeFeature = "linkname"
+	// UnsafePackage is a use of an identifier of package unsafe.
+	UnsafePackage UnsafeFeature = "unsafe"
+)
+
+func validateUnsafeFeatures(where string, features []UnsafeFeature) error {
+	for _, feature := range features {
+		if feature != UnsafeLinkname && feature != UnsafePackage {
+			return fmt.Errorf("%s: invalid unsafe feature %q: must be %q or %q", where, feature, UnsafeLinkname, UnsafePackage)
+		}
+	}
+	return nil
+}
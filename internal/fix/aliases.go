@@ -0,0 +1,184 @@
+// Package fix rewrites source files to resolve violations.
+package fix
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/coderhyme/arch-lint/internal/checker"
+	"github.com/coderhyme/arch-lint/internal/loader"
+)
+
+// Aliases renames the imports of alias violations that require a name, together with the
+// selector expressions that refer to them, in files below rootPath. An import is left alone
+// when its new name is already used in the file or declared at package level in another file
+// of its package. packageName resolves the package names of imports that are not renamed and
+// reports whether the name is known; an import whose name is only assumed is left alone, since
+// the selectors referring to it cannot be found. It returns the violations it fixed.
+func Aliases(rootPath string, violations []checker.Violation, packageName func(importPath string) (string, bool)) ([]checker.Violation, []error) {
+	byFile := make(map[string][]checker.Violation)
+	for _, v := range violations {
+		if v.WantAlias != "" && v.Pos.Filename != "" {
+			byFile[v.Pos.Filename] = append(byFile[v.Pos.Filename], v)
+		}
+	}
+
+	files := make([]string, 0, len(byFile))
+	for file := range byFile {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	var fixed []checker.Violation
+	var errs []error
+	for _, file := range files {
+		done, err := renameImports(filepath.Join(rootPath, filepath.FromSlash(file)), byFile[file], packageName)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to fix aliases in %s: %w", file, err))
+		}
+		fixed = append(fixed, done...)
+	}
+	return fixed, errs
+}
+
+func renameImports(path string, violations []checker.Violation, packageName func(importPath string) (string, bool)) ([]checker.Violation, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, nil, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	uses := loader.ImportUses(fset, file, func(importPath string) string {
+		name, _ := packageName(importPath)
+		return name
+	})
+	used, err := packageNames(path, file.Name.Name)
+	if err != nil {
+		return nil, err
+	}
+	ast.Inspect(file, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok {
+			used[ident.Name] = true
+		}
+		return true
+	})
+
+	var fixed []checker.Violation
+	var errs []string
+	renames := make(map[*ast.ImportSpec]string)
+	for _, v := range violations {
+		spec := importAt(fset, file, v)
+		if spec == nil {
+			errs = append(errs, fmt.Sprintf("no import of %s at line %d", v.Import, v.Pos.Line))
+			continue
+		}
+		if spec.Name == nil {
+			if _, known := packageName(strings.Trim(spec.Path.Value, `"`)); !known {
+				errs = append(errs, fmt.Sprintf("cannot rename %s to %s: the package name of %s is unknown", v.Alias, v.WantAlias, v.Import))
+				continue
+			}
+		}
+		if used[v.WantAlias] {
+			errs = append(errs, fmt.Sprintf("cannot rename %s to %s: the name is already used", v.Alias, v.WantAlias))
+			continue
+		}
+
+		spec.Name = &ast.Ident{NamePos: spec.Path.Pos(), Name: v.WantAlias}
+		renames[spec] = v.WantAlias
+		used[v.WantAlias] = true
+		fixed = append(fixed, v)
+	}
+
+	if len(renames) > 0 {
+		for _, use := range uses {
+			if alias, ok := renames[use.Spec]; ok {
+				use.Ident.Name = alias
+			}
+		}
+
+		var buf bytes.Buffer
+		if err := format.Node(&buf, fset, file); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(path, buf.Bytes(), info.Mode().Perm()); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(errs) > 0 {
+		return fixed, fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return fixed, nil
+}
+
+// packageNames returns the names declared at package level by the other files of package
+// pkgName in the directory of path, which share the package scope with the file at path.
+// Test files are included, since they may belong to the same package.
+func packageNames(path, pkgName string) (map[string]bool, error) {
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool)
+	fset := token.NewFileSet()
+	for _, entry := range entries {
+		sibling := filepath.Join(filepath.Dir(path), entry.Name())
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") || sibling == path {
+			continue
+		}
+		file, err := parser.ParseFile(fset, sibling, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		if file.Name.Name != pkgName {
+			continue
+		}
+
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if decl.Recv == nil {
+					names[decl.Name.Name] = true
+				}
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					switch spec := spec.(type) {
+					case *ast.TypeSpec:
+						names[spec.Name.Name] = true
+					case *ast.ValueSpec:
+						for _, name := range spec.Names {
+							names[name.Name] = true
+						}
+					}
+				}
+			}
+		}
+	}
+	return names, nil
+}
+
+// importAt returns the import declared at the line of v for the package v imports, which
+// is relative to the module root for module packages.
+func importAt(fset *token.FileSet, file *ast.File, v checker.Violation) *ast.ImportSpec {
+	for _, spec := range file.Imports {
+		importPath := strings.Trim(spec.Path.Value, `"`)
+		if fset.Position(spec.Pos()).Line == v.Pos.Line && (importPath == v.Import || strings.HasSuffix(importPath, "/"+v.Import)) {
+			return spec
+		}
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package fix
+
+import (
+	"context"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/coderhyme/arch-lint/internal/checker"
+	"github.com/coderhyme/arch-lint/internal/loader"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type FixSuite struct {
+	suite.Suite
+	root  string
+	names *loader.PackageNamer
+}
+
+func TestFixSuite(t *testing.T) {
+	suite.Run(t, new(FixSuite))
+}
+
+func (s *FixSuite) SetupTest() {
+	s.root = s.T().TempDir()
+	s.names = loader.NewPackageNamer(s.root, "github.com/example/app")
+}
+
+func (s *FixSuite) write(name, content string) {
+	path := filepath.Join(s.root, name)
+	s.Require().NoError(os.MkdirAll(filepath.Dir(path), 0o755))
+	s.Require().NoError(os.WriteFile(path, []byte(content), 0o644))
+}
+
+func (s *FixSuite) TestAliases_RenamesImportAndSelectors() {
+	// given
+	s.write("internal/api/user.go", `package api
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+func Describe(p *v1.Pod) string {
+	return fmt.Sprint(p.Name, v1.NamespaceDefault)
+}
+`)
+	violations := []checker.Violation{
+		{Package: "internal/api", Import: "k8s.io/api/core/v1", Alias: "v1", WantAlias: "corev1",
+			Pos: token.Position{Filename: "internal/api/user.go", Line: 6}},
+		{Package: "internal/api", Import: "fmt", Alias: "fmt",
+			Pos: token.Position{Filename: "internal/api/user.go", Line: 4}},
+	}
+
+	// when
+	fixed, errs := Aliases(s.root, violations, s.names.Resolve)
+
+	// then
+	assert.Empty(s.T(), errs)
+	assert.Equal(s.T(), violations[:1], fixed)
+	content, err := os.ReadFile(filepath.Join(s.root, "internal/api/user.go"))
+	s.Require().NoError(err)
+	assert.Equal(s.T(), `package api
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Describe(p *corev1.Pod) string {
+	return fmt.Sprint(p.Name, corev1.NamespaceDefault)
+}
+`, string(content))
+}
+
+func (s *FixSuite) TestAliases_SkipsNamesInUse() {
+	// given
+	source := `package api
+
+import v1 "k8s.io/api/core/v1"
+
+var corev1 = v1.NamespaceDefault
+`
+	s.write("internal/api/user.go", source)
+	violations := []checker.Violation{
+		{Package: "internal/api", Import: "k8s.io/api/core/v1", Alias: "v1", WantAlias: "corev1",
+			Pos: token.Position{Filename: "internal/api/user.go", Line: 3}},
+	}
+
+	// when
+	fixed, errs := Aliases(s.root, violations, s.names.Resolve)
+
+	// then
+	assert.Empty(s.T(), fixed)
+	assert.Len(s.T(), errs, 1)
+	content, err := os.ReadFile(filepath.Join(s.root, "internal/api/user.go"))
+	s.Require().NoError(err)
+	assert.Equal(s.T(), source, string(content))
+}
+
+func (s *FixSuite) TestAliases_SkipsNamesDeclaredInOtherFilesOfPackage() {
+	// given
+	source := `package api
+
+import v1 "k8s.io/api/core/v1"
+
+var namespace = v1.NamespaceDefault
+`
+	s.write("internal/api/user.go", source)
+	s.write("internal/api/clients.go", "package api\n\nvar corev1 = 1\n")
+	s.write("internal/api/api_test.go", "package api_test\n\nvar metav1 = 1\n")
+	violations := []checker.Violation{
+		{Package: "internal/api", Import: "k8s.io/api/core/v1", Alias: "v1", WantAlias: "corev1",
+			Pos: token.Position{Filename: "internal/api/user.go", Line: 3}},
+	}
+
+	// when
+	fixed, errs := Aliases(s.root, violations, s.names.Resolve)
+
+	// then
+	assert.Empty(s.T(), fixed)
+	s.Require().Len(errs, 1)
+	assert.ErrorContains(s.T(), errs[0], "the name is already used")
+	content, err := os.ReadFile(filepath.Join(s.root, "internal/api/user.go"))
+	s.Require().NoError(err)
+	assert.Equal(s.T(), source, string(content))
+}
+
+func (s *FixSuite) TestAliases_IgnoresNamesOfOtherPackagesInDirectory() {
+	// given
+	s.write("internal/api/user.go", "package api\n\nimport v1 \"k8s.io/api/core/v1\"\n\nvar namespace = v1.NamespaceDefault\n")
+	s.write("internal/api/api_test.go", "package api_test\n\nvar corev1 = 1\n")
+	violations := []checker.Violation{
+		{Package: "internal/api", Import: "k8s.io/api/core/v1", Alias: "v1", WantAlias: "corev1",
+			Pos: token.Position{Filename: "internal/api/user.go", Line: 3}},
+	}
+
+	// when
+	fixed, errs := Aliases(s.root, violations, s.names.Resolve)
+
+	// then - the external test package has its own scope
+	assert.Empty(s.T(), errs)
+	assert.Equal(s.T(), violations, fixed)
+}
+
+func (s *FixSuite) TestAliases_KeepsLocalNamesShadowingImport() {
+	// given
+	s.write("internal/api/user.go", `package api
+
+import v1 "k8s.io/api/core/v1"
+
+func Name(p *v1.Pod) string {
+	if v1 := p; v1 != nil {
+		return v1.Name
+	}
+	return v1.NamespaceDefault
+}
+`)
+	violations := []checker.Violation{
+		{Package: "internal/api", Import: "k8s.io/api/core/v1", Alias: "v1", WantAlias: "corev1",
+			Pos: token.Position{Filename: "internal/api/user.go", Line: 3}},
+	}
+
+	// when
+	fixed, errs := Aliases(s.root, violations, s.names.Resolve)
+
+	// then
+	assert.Empty(s.T(), errs)
+	assert.Equal(s.T(), violations, fixed)
+	content, err := os.ReadFile(filepath.Join(s.root, "internal/api/user.go"))
+	s.Require().NoError(err)
+	assert.Equal(s.T(), `package api
+
+import corev1 "k8s.io/api/core/v1"
+
+func Name(p *corev1.Pod) string {
+	if v1 := p; v1 != nil {
+		return v1.Name
+	}
+	return corev1.NamespaceDefault
+}
+`, string(content))
+}
+
+func (s *FixSuite) TestAliases_RenamesUnrenamedImportOfVersionedPath() {
+	// given
+	s.write("go.mod", "module github.com/example/app\n\ngo 1.22\n\nrequire k8s.io/api v0.0.0\n\nreplace k8s.io/api => ./third_party/api\n")
+	s.write("third_party/api/go.mod", "module k8s.io/api\n\ngo 1.22\n")
+	s.write("third_party/api/core/v1/types.go", "package v1\n\ntype Pod struct{}\n")
+	s.write("internal/api/user.go", "package api\n\nimport \"k8s.io/api/core/v1\"\n\nvar pod v1.Pod\n")
+	s.Require().NoError(s.names.Load(context.Background(), []string{"k8s.io/api/core/v1"}))
+	violations := []checker.Violation{
+		{Package: "internal/api", Import: "k8s.io/api/core/v1", Alias: "v1", WantAlias: "corev1",
+			Pos: token.Position{Filename: "internal/api/user.go", Line: 3}},
+	}
+
+	// when
+	fixed, errs := Aliases(s.root, violations, s.names.Resolve)
+
+	// then
+	assert.Empty(s.T(), errs)
+	assert.Equal(s.T(), violations, fixed)
+	content, err := os.ReadFile(filepath.Join(s.root, "internal/api/user.go"))
+	s.Require().NoError(err)
+	assert.Equal(s.T(), "package api\n\nimport corev1 \"k8s.io/api/core/v1\"\n\nvar pod corev1.Pod\n", string(content))
+}
+
+func (s *FixSuite) TestAliases_SkipsImportsWithUnknownPackageName() {
+	// given
+	source := "package api\n\nimport \"k8s.io/api/core/v1\"\n\nvar pod v1.Pod\n"
+	s.write("internal/api/user.go", source)
+	violations := []checker.Violation{
+		{Package: "internal/api", Import: "k8s.io/api/core/v1", Alias: "core", WantAlias: "corev1",
+			Pos: token.Position{Filename: "internal/api/user.go", Line: 3}},
+	}
+
+	// when
+	fixed, errs := Aliases(s.root, violations, s.names.Resolve)
+
+	// then
+	assert.Empty(s.T(), fixed)
+	s.Require().Len(errs, 1)
+	assert.ErrorContains(s.T(), errs[0], "the package name of k8s.io/api/core/v1 is unknown")
+	content, err := os.ReadFile(filepath.Join(s.root, "internal/api/user.go"))
+	s.Require().NoError(err)
+	assert.Equal(s.T(), source, string(content))
+}
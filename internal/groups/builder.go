@@ -2,54 +2,180 @@ package groups
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/coderhyme/arch-lint/internal/config"
 )
 
 func newGroup(ctx context.Context, name string, cfg *config.Group, manager GroupManager) (Group, error) {
-	pathMatchers := buildPathMatchers(cfg.Paths)
+	pathMatchers, err := buildPathMatchers(cfg.Paths)
+	if err != nil {
+		return nil, err
+	}
+
+	captures := make(map[string]bool)
+	for _, path := range cfg.Paths {
+		for _, capture := range captureNames(path.Dir) {
+			captures[capture] = true
+		}
+	}
 
 	var denyRules []ImportRule
+	var layerRules []ImportRule
 	var allowRules []ImportRule
+	var denySymbols []*symbolRule
+	var allowSymbols []*symbolRule
 
 	if cfg.Dependencies != nil {
 		if cfg.Dependencies.Deny != nil {
-			rules, err := buildDependencyMatchers(ctx, cfg.Dependencies.Deny, manager)
+			rules, err := buildDependencyMatchers(ctx, cfg.Dependencies.Deny, captures, manager)
 			if err != nil {
 				return nil, err
 			}
 			denyRules = append(denyRules, rules...)
+
+			denySymbols, err = buildSymbolRules(cfg.Dependencies.Deny)
+			if err != nil {
+				return nil, err
+			}
 		}
 		if cfg.Dependencies.Allow != nil {
-			rules, err := buildDependencyMatchers(ctx, cfg.Dependencies.Allow, manager)
+			rules, err := buildDependencyMatchers(ctx, cfg.Dependencies.Allow, captures, manager)
 			if err != nil {
 				return nil, err
 			}
 			allowRules = append(allowRules, rules...)
+
+			allowSymbols, err = buildSymbolRules(cfg.Dependencies.Allow)
+			if err != nil {
+				return nil, err
+			}
+			for _, rule := range allowSymbols {
+				allowRules = append(allowRules, rule)
+			}
+		}
+		if cfg.Dependencies.Layer != nil {
+			rules, err := buildLayerMatchers(ctx, cfg.Dependencies.Layer, manager)
+			if err != nil {
+				return nil, err
+			}
+			layerRules = append(layerRules, rules...)
+		}
+	}
+
+	var callRules []*callRule
+	for _, pattern := range cfg.ForbiddenCalls {
+		rule, err := newCallRule(pattern)
+		if err != nil {
+			return nil, err
+		}
+		callRules = append(callRules, rule)
+	}
+
+	groupSeverity := cfg.Severity.Or(config.SeverityError)
+	denySeverity, allowSeverity := groupSeverity, groupSeverity
+	if cfg.Dependencies != nil && cfg.Dependencies.Deny != nil {
+		denySeverity = cfg.Dependencies.Deny.Severity.Or(groupSeverity)
+	}
+	if cfg.Dependencies != nil && cfg.Dependencies.Allow != nil {
+		allowSeverity = cfg.Dependencies.Allow.Severity.Or(groupSeverity)
+	}
+
+	var friends []Group
+	for _, ref := range cfg.Friends {
+		friend, err := manager.GetGroup(ctx, ref)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve friend: %w", err)
+		}
+		friends = append(friends, friend)
+	}
+
+	var exports *exportRules
+	if cfg.Exports != nil {
+		exports, err = buildExportRules(ctx, cfg.Exports, captures, groupSeverity, manager)
+		if err != nil {
+			return nil, err
 		}
 	}
 
+	var denyMessage, allowMessage config.RuleMessage
+	if cfg.Dependencies != nil && cfg.Dependencies.Deny != nil {
+		denyMessage = config.RuleMessage{Message: cfg.Dependencies.Deny.Message, Docs: cfg.Dependencies.Deny.Docs}
+	}
+	if cfg.Dependencies != nil && cfg.Dependencies.Allow != nil {
+		allowMessage = config.RuleMessage{Message: cfg.Dependencies.Allow.Message, Docs: cfg.Dependencies.Allow.Docs}
+	}
+
 	return &groupWithRules{
-		name:         name,
-		pathMatchers: pathMatchers,
-		denyRules:    denyRules,
-		allowRules:   allowRules,
+		name:          name,
+		source:        cfg.Source,
+		pathMatchers:  pathMatchers,
+		denyRules:     denyRules,
+		layerRules:    layerRules,
+		allowRules:    allowRules,
+		denySymbols:   denySymbols,
+		allowSymbols:  allowSymbols,
+		callRules:     callRules,
+		importForms:   cfg.ForbiddenImportForms,
+		allowUnsafe:   cfg.AllowUnsafe,
+		exports:       exports,
+		private:       cfg.Private,
+		friends:       friends,
+		transitive:    cfg.Dependencies != nil && cfg.Dependencies.Deny != nil && cfg.Dependencies.Deny.Transitive,
+		limits:        Limits{MaxImports: cfg.MaxImports, MaxImporters: cfg.MaxImporters, Severity: groupSeverity},
+		severity:      groupSeverity,
+		denySeverity:  denySeverity,
+		allowSeverity: allowSeverity,
+		denyMessage:   denyMessage,
+		allowMessage:  allowMessage,
 	}, nil
 }
 
-func buildPathMatchers(paths config.PathConfigs) []Matcher {
+func buildPathMatchers(paths config.PathConfigs) ([]Matcher, error) {
 	var matchers []Matcher
 	for _, path := range paths {
-		matchers = append(matchers, NewGlobMatcher(path.Dir))
+		if len(captureNames(path.Dir)) == 0 {
+			matchers = append(matchers, NewGlobMatcher(path.Dir))
+			continue
+		}
+
+		matcher, err := NewCaptureMatcher(path.Dir)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, matcher)
 	}
-	return matchers
+	return matchers, nil
 }
 
-func buildDependencyMatchers(ctx context.Context, rule *config.DependencyRule, manager GroupManager) ([]ImportRule, error) {
+func buildDependencyMatchers(ctx context.Context, rule *config.DependencyRule, captures map[string]bool, manager GroupManager) ([]ImportRule, error) {
 	var matchers []ImportRule
 
 	for _, pattern := range rule.Patterns {
-		matchers = append(matchers, NewGlobImportRule(pattern))
+		names := captureNames(pattern)
+		if len(names) == 0 {
+			matchers = append(matchers, NewGlobImportRule(pattern))
+			continue
+		}
+
+		for _, name := range names {
+			if !captures[name] {
+				return nil, fmt.Errorf("pattern %q references {%s}, which is not captured by the group paths", pattern, name)
+			}
+		}
+		matcher, err := NewCaptureImportRule(pattern)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, matcher)
+	}
+
+	for i, pattern := range rule.Patterns {
+		if msg, ok := rule.PatternMessages[pattern]; ok {
+			matchers[i] = &describedImportRule{ImportRule: matchers[i], message: msg}
+		}
 	}
 
 	for _, rel := range rule.Relative {
@@ -71,17 +197,106 @@ func buildDependencyMatchers(ctx context.Context, rule *config.DependencyRule, m
 	return matchers, nil
 }
 
+func buildLayerMatchers(ctx context.Context, layer *config.LayerRule, manager GroupManager) ([]ImportRule, error) {
+	var matchers []ImportRule
+
+	for _, groupName := range layer.Above {
+		grp, err := manager.GetGroup(ctx, groupName)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, NewLayerImportRule(layer.Layer, grp, true))
+	}
+
+	for _, groupName := range layer.Skipped {
+		grp, err := manager.GetGroup(ctx, groupName)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, NewLayerImportRule(layer.Layer, grp, false))
+	}
+
+	return matchers, nil
+}
+
 type groupWithRules struct {
 	name         string
+	source       string
 	pathMatchers []Matcher
 	denyRules    []ImportRule
+	layerRules   []ImportRule
 	allowRules   []ImportRule
+	denySymbols  []*symbolRule
+	allowSymbols []*symbolRule
+	callRules    []*callRule
+	importForms  []config.ImportForm
+	allowUnsafe  []config.UnsafeFeature
+	exports      *exportRules
+	// private restricts importers to the group itself and friends.
+	private bool
+	friends []Group
+	// transitive makes the deny rules also apply to packages reached indirectly.
+	transitive bool
+	limits     Limits
+	// severity applies to layer violations, forbidden calls, import forms and unsafe
+	// features, denySeverity to deny matches and allowSeverity to imports no allow rule
+	// matches.
+	severity      config.Severity
+	denySeverity  config.Severity
+	allowSeverity config.Severity
+	// denyMessage and allowMessage explain deny matches and imports no allow rule matches,
+	// unless the matching pattern has a message of its own.
+	denyMessage  config.RuleMessage
+	allowMessage config.RuleMessage
+}
+
+// describedImportRule is a rule pattern with its own message.
+type describedImportRule struct {
+	ImportRule
+	message config.RuleMessage
 }
 
 func (p *groupWithRules) Name() string {
 	return p.name
 }
 
+func (p *groupWithRules) Source() string {
+	return p.source
+}
+
+func (p *groupWithRules) DecideImporter(importer string) Decision {
+	if !p.private || p.MatchPath(importer) {
+		return Decision{Allowed: true}
+	}
+	for _, friend := range p.friends {
+		if friend.MatchPath(importer) {
+			return Decision{Allowed: true}
+		}
+	}
+	rule := "private"
+	if len(p.friends) > 0 {
+		names := make([]string, 0, len(p.friends))
+		for _, friend := range p.friends {
+			names = append(names, strconv.Quote(friend.Name()))
+		}
+		rule = fmt.Sprintf("private, friends %s", strings.Join(names, ", "))
+	}
+	return Decision{Rule: rule, Severity: p.severity}
+}
+
+func (p *groupWithRules) DecideUnsafe(feature config.UnsafeFeature) Decision {
+	for _, allowed := range p.allowUnsafe {
+		if allowed == feature {
+			return Decision{Allowed: true}
+		}
+	}
+	return Decision{Rule: fmt.Sprintf("%s not allowed", feature), Severity: p.severity}
+}
+
+func (p *groupWithRules) Limits() Limits {
+	return p.limits
+}
+
 func (p *groupWithRules) MatchPath(path string) bool {
 	for _, matcher := range p.pathMatchers {
 		if matcher.Match(path) {
@@ -92,35 +307,145 @@ func (p *groupWithRules) MatchPath(path string) bool {
 }
 
 func (p *groupWithRules) GetDependencyChecker(path string) DependencyChecker {
-	return &ruleBasedChecker{
+	vars := p.bindCaptures(path)
+	checker := &ruleBasedChecker{
 		packagePath: path,
-		denyRules:   p.denyRules,
-		allowRules:  p.allowRules,
+		denyRules:   bindRules(p.denyRules, vars),
+		layerRules:  p.layerRules,
+		allowRules:  bindRules(p.allowRules, vars),
+		group:       p,
+	}
+	if p.exports != nil {
+		checker.exportDeny = bindRules(p.exports.deny, vars)
+		checker.exportAllow = bindRules(p.exports.allow, vars)
+	}
+	return checker
+}
+
+// bindCaptures returns the capture variables bound by the first path pattern matching path.
+func (p *groupWithRules) bindCaptures(path string) map[string]string {
+	for _, matcher := range p.pathMatchers {
+		if cm, ok := matcher.(*captureMatcher); ok {
+			if vars, ok := cm.Bind(path); ok {
+				return vars
+			}
+		} else if matcher.Match(path) {
+			return nil
+		}
 	}
+	return nil
 }
 
 type ruleBasedChecker struct {
 	packagePath string
 	denyRules   []ImportRule
+	layerRules  []ImportRule
 	allowRules  []ImportRule
+	exportDeny  []ImportRule
+	exportAllow []ImportRule
+	group       *groupWithRules
 }
 
 func (r *ruleBasedChecker) CanDependOn(importPath string) bool {
-	if len(r.denyRules) == 0 && len(r.allowRules) == 0 {
-		return true
+	return r.Decide(importPath).Allowed
+}
+
+func (r *ruleBasedChecker) Decide(importPath string) Decision {
+	if len(r.denyRules) == 0 && len(r.layerRules) == 0 && len(r.allowRules) == 0 {
+		return Decision{Allowed: true}
 	}
 
 	for _, rule := range r.denyRules {
 		if rule.Allows(r.packagePath, importPath) {
-			return false
+			msg := r.group.denyMessage
+			if dr, ok := rule.(*describedImportRule); ok {
+				msg = dr.message
+			}
+			return Decision{Rule: "deny " + rule.String(), Severity: r.group.denySeverity, Message: msg.Message, Docs: msg.Docs}
+		}
+	}
+
+	for _, rule := range r.layerRules {
+		if rule.Allows(r.packagePath, importPath) {
+			return Decision{Rule: rule.String(), Severity: r.group.severity}
 		}
 	}
+	// Layer rules alone only forbid imports; they do not restrict the group to an allow list.
+	if len(r.denyRules) == 0 && len(r.allowRules) == 0 {
+		return Decision{Allowed: true}
+	}
 
 	for _, rule := range r.allowRules {
 		if rule.Allows(r.packagePath, importPath) {
-			return true
+			return Decision{Allowed: true}
 		}
 	}
 
-	return false
+	msg := r.group.allowMessage
+	return Decision{Rule: "not allowed", Severity: r.group.allowSeverity, Message: msg.Message, Docs: msg.Docs}
+}
+
+// DecideSymbol checks the use of the identifier name of importPath. Deny symbols forbid
+// the identifiers they match. Once an allow symbol matches importPath, only identifiers
+// matched by an allow symbol may be used from it.
+func (r *ruleBasedChecker) DecideSymbol(importPath, name string) Decision {
+	for _, rule := range r.group.denySymbols {
+		if rule.Matches(importPath, name) {
+			return Decision{Rule: "deny " + rule.String(), Severity: r.group.denySeverity, Message: rule.message.Message, Docs: rule.message.Docs}
+		}
+	}
+
+	restricted := false
+	for _, rule := range r.group.allowSymbols {
+		if rule.Matches(importPath, name) {
+			return Decision{Allowed: true}
+		}
+		restricted = restricted || rule.Allows(r.packagePath, importPath)
+	}
+	if restricted {
+		msg := r.group.allowMessage
+		return Decision{Rule: "symbol not allowed", Severity: r.group.allowSeverity, Message: msg.Message, Docs: msg.Docs}
+	}
+
+	return Decision{Allowed: true}
+}
+
+// DecideCall checks a call of the function, method or builtin name against the group's
+// forbidden calls.
+func (r *ruleBasedChecker) DecideCall(name string) Decision {
+	for _, rule := range r.group.callRules {
+		if rule.Matches(name) {
+			return Decision{Rule: rule.String(), Severity: r.group.severity}
+		}
+	}
+	return Decision{Allowed: true}
+}
+
+// DecideImportForm checks an import form against the group's forbidden import forms.
+func (r *ruleBasedChecker) DecideImportForm(form config.ImportForm) Decision {
+	for _, forbidden := range r.group.importForms {
+		if forbidden == form {
+			return Decision{Rule: fmt.Sprintf("forbidden import form %q", form), Severity: r.group.severity}
+		}
+	}
+	return Decision{Allowed: true}
+}
+
+// DecideTransitive checks a package reached through other imports, which only the deny
+// rules of a transitive deny section apply to.
+func (r *ruleBasedChecker) DecideTransitive(importPath string) Decision {
+	if !r.group.transitive {
+		return Decision{Allowed: true}
+	}
+
+	for _, rule := range r.denyRules {
+		if rule.Allows(r.packagePath, importPath) {
+			msg := r.group.denyMessage
+			if dr, ok := rule.(*describedImportRule); ok {
+				msg = dr.message
+			}
+			return Decision{Rule: "transitive deny " + rule.String(), Severity: r.group.denySeverity, Message: msg.Message, Docs: msg.Docs}
+		}
+	}
+	return Decision{Allowed: true}
 }
@@ -0,0 +1,35 @@
+package groups
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gobwas/glob"
+)
+
+// callRule matches the names of called functions, methods and builtins. A "(*" in the
+// pattern is literal, so pointer receivers can be written as in "(*log.Logger).Fatal", and
+// so are brackets and braces, so methods of generic types can be written as in
+// "(*internal/list.List[T]).Push". Only "*" and "?" are wildcards.
+type callRule struct {
+	pattern string
+	g       glob.Glob
+}
+
+var callPatternEscaper = strings.NewReplacer("(*", `(\*`, "[", `\[`, "]", `\]`, "{", `\{`, "}", `\}`)
+
+func newCallRule(pattern string) (*callRule, error) {
+	g, err := glob.Compile(callPatternEscaper.Replace(pattern))
+	if err != nil {
+		return nil, fmt.Errorf("invalid forbidden call %q: %w", pattern, err)
+	}
+	return &callRule{pattern: pattern, g: g}, nil
+}
+
+func (r *callRule) Matches(name string) bool {
+	return r.g.Match(name)
+}
+
+func (r *callRule) String() string {
+	return fmt.Sprintf("forbidden call %q", r.pattern)
+}
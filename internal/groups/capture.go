@@ -0,0 +1,227 @@
+package groups
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/gobwas/glob"
+)
+
+// captureVar matches a capture variable such as {module}. Braces holding a comma-separated list
+// keep their glob meaning of alternatives.
+var captureVar = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+func captureNames(pattern string) []string {
+	var names []string
+	for _, m := range captureVar.FindAllStringSubmatch(pattern, -1) {
+		names = append(names, m[1])
+	}
+	return names
+}
+
+// captureMatcher matches a path pattern containing capture variables. Each variable matches a
+// single path segment whose value is bound for the matching package.
+type captureMatcher struct {
+	re *regexp.Regexp
+}
+
+func NewCaptureMatcher(pattern string) (Matcher, error) {
+	expr, err := globToRegexp(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path pattern %q: %w", pattern, err)
+	}
+	re, err := regexp.Compile("^" + expr + "$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid path pattern %q: %w", pattern, err)
+	}
+	return &captureMatcher{re: re}, nil
+}
+
+func (m *captureMatcher) Match(path string) bool {
+	return m.re.MatchString(path)
+}
+
+// Bind returns the values captured from path.
+func (m *captureMatcher) Bind(path string) (map[string]string, bool) {
+	match := m.re.FindStringSubmatch(path)
+	if match == nil {
+		return nil, false
+	}
+
+	vars := make(map[string]string)
+	for i, name := range m.re.SubexpNames() {
+		if name != "" {
+			vars[name] = match[i]
+		}
+	}
+	return vars, true
+}
+
+// globToRegexp translates the gobwas/glob syntax used by path patterns into a regular
+// expression, turning capture variables into named single-segment groups.
+func globToRegexp(pattern string) (string, error) {
+	var sb strings.Builder
+	depth := 0
+	seen := make(map[string]bool)
+
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch c {
+		case '\\':
+			if i+1 >= len(pattern) {
+				return "", fmt.Errorf("trailing escape")
+			}
+			i++
+			sb.WriteString(regexp.QuoteMeta(string(pattern[i])))
+		case '*':
+			for i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+			}
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteString(".")
+		case '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end < 0 {
+				return "", fmt.Errorf("unterminated character class")
+			}
+			class := pattern[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + class + "]")
+			i += end
+		case '{':
+			if loc := captureVar.FindStringSubmatchIndex(pattern[i:]); loc != nil && loc[0] == 0 {
+				name := pattern[i+loc[2] : i+loc[3]]
+				if seen[name] {
+					return "", fmt.Errorf("capture variable {%s} used more than once", name)
+				}
+				seen[name] = true
+				sb.WriteString("(?P<" + name + ">[^/]+)")
+				i += loc[1] - 1
+				continue
+			}
+			depth++
+			sb.WriteString("(?:")
+		case ',':
+			if depth > 0 {
+				sb.WriteString("|")
+			} else {
+				sb.WriteString(",")
+			}
+		case '}':
+			if depth == 0 {
+				return "", fmt.Errorf("unbalanced braces")
+			}
+			depth--
+			sb.WriteString(")")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	if depth != 0 {
+		return "", fmt.Errorf("unbalanced braces")
+	}
+	return sb.String(), nil
+}
+
+// boundGlobImportRule is a glob rule whose pattern references capture variables of the
+// importing package's group.
+type boundGlobImportRule struct {
+	pattern string
+	vars    map[string]string
+	cache   *globCache
+}
+
+type globCache struct {
+	mu    sync.Mutex
+	globs map[string]glob.Glob
+}
+
+func (c *globCache) get(pattern string) glob.Glob {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if g, exists := c.globs[pattern]; exists {
+		return g
+	}
+	// NewCaptureImportRule validated the pattern, and substituted values are escaped.
+	g := glob.MustCompile(pattern)
+	c.globs[pattern] = g
+	return g
+}
+
+// NewCaptureImportRule creates a glob rule whose capture variables are substituted with the
+// values bound from the importing package before matching. Values are escaped, so the
+// pattern is validated once with a placeholder value.
+func NewCaptureImportRule(pattern string) (ImportRule, error) {
+	if _, err := glob.Compile(captureVar.ReplaceAllString(pattern, "x")); err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	return &boundGlobImportRule{
+		pattern: pattern,
+		cache:   &globCache{globs: make(map[string]glob.Glob)},
+	}, nil
+}
+
+func (m *boundGlobImportRule) Allows(_, toImport string) bool {
+	if m.vars == nil {
+		return false
+	}
+
+	resolved := captureVar.ReplaceAllStringFunc(m.pattern, func(v string) string {
+		return escapeGlob(m.vars[v[1:len(v)-1]])
+	})
+	return m.cache.get(resolved).Match(toImport)
+}
+
+func (m *boundGlobImportRule) String() string {
+	return fmt.Sprintf("pattern %q", m.pattern)
+}
+
+func (m *boundGlobImportRule) bind(vars map[string]string) ImportRule {
+	return &boundGlobImportRule{
+		pattern: m.pattern,
+		vars:    vars,
+		cache:   m.cache,
+	}
+}
+
+// bindRules returns rules with capture variables bound to vars.
+func bindRules(rules []ImportRule, vars map[string]string) []ImportRule {
+	if len(vars) == 0 {
+		return rules
+	}
+
+	bound := make([]ImportRule, len(rules))
+	for i, rule := range rules {
+		bound[i] = bindRule(rule, vars)
+	}
+	return bound
+}
+
+func bindRule(rule ImportRule, vars map[string]string) ImportRule {
+	switch r := rule.(type) {
+	case *boundGlobImportRule:
+		return r.bind(vars)
+	case *describedImportRule:
+		return &describedImportRule{ImportRule: bindRule(r.ImportRule, vars), message: r.message}
+	default:
+		return rule
+	}
+}
+
+func escapeGlob(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(`*?[]{}\`, r) {
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
@@ -0,0 +1,72 @@
+package groups
+
+import (
+	"context"
+
+	"github.com/coderhyme/arch-lint/internal/config"
+)
+
+// exportRules restricts the packages whose types a group's exported API may reference.
+type exportRules struct {
+	deny          []ImportRule
+	allow         []ImportRule
+	denySeverity  config.Severity
+	allowSeverity config.Severity
+	denyMessage   config.RuleMessage
+	allowMessage  config.RuleMessage
+}
+
+func buildExportRules(ctx context.Context, exports *config.Dependencies, captures map[string]bool, groupSeverity config.Severity, manager GroupManager) (*exportRules, error) {
+	rules := &exportRules{denySeverity: groupSeverity, allowSeverity: groupSeverity}
+
+	if exports.Deny != nil {
+		deny, err := buildDependencyMatchers(ctx, exports.Deny, captures, manager)
+		if err != nil {
+			return nil, err
+		}
+		rules.deny = deny
+		rules.denySeverity = exports.Deny.Severity.Or(groupSeverity)
+		rules.denyMessage = config.RuleMessage{Message: exports.Deny.Message, Docs: exports.Deny.Docs}
+	}
+	if exports.Allow != nil {
+		allow, err := buildDependencyMatchers(ctx, exports.Allow, captures, manager)
+		if err != nil {
+			return nil, err
+		}
+		rules.allow = allow
+		rules.allowSeverity = exports.Allow.Severity.Or(groupSeverity)
+		rules.allowMessage = config.RuleMessage{Message: exports.Allow.Message, Docs: exports.Allow.Docs}
+	}
+
+	return rules, nil
+}
+
+// DecideExport checks a type of the package typePackage referenced by the exported API.
+// Types of the group's own packages may always be exposed. Deny rules forbid the packages
+// they match, and allow rules, when present, are the only other packages that may be exposed.
+func (r *ruleBasedChecker) DecideExport(typePackage string) Decision {
+	ex := r.group.exports
+	if ex == nil || r.group.MatchPath(typePackage) {
+		return Decision{Allowed: true}
+	}
+
+	for _, rule := range r.exportDeny {
+		if rule.Allows(r.packagePath, typePackage) {
+			msg := ex.denyMessage
+			if dr, ok := rule.(*describedImportRule); ok {
+				msg = dr.message
+			}
+			return Decision{Rule: "exports deny " + rule.String(), Severity: ex.denySeverity, Message: msg.Message, Docs: msg.Docs}
+		}
+	}
+
+	if len(r.exportAllow) == 0 {
+		return Decision{Allowed: true}
+	}
+	for _, rule := range r.exportAllow {
+		if rule.Allows(r.packagePath, typePackage) {
+			return Decision{Allowed: true}
+		}
+	}
+	return Decision{Rule: "exports not allowed", Severity: ex.allowSeverity, Message: ex.allowMessage.Message, Docs: ex.allowMessage.Docs}
+}
@@ -1,18 +1,62 @@
 package groups
 
-import "context"
+import (
+	"context"
+
+	"github.com/coderhyme/arch-lint/internal/config"
+)
 
 type GroupManager interface {
 	GetGroups(ctx context.Context, path string) ([]Group, error)
 	GetGroup(ctx context.Context, name string) (Group, error)
+	ListGroups(ctx context.Context) ([]Group, error)
 }
 
 type DependencyChecker interface {
 	CanDependOn(importPath string) bool
+	Decide(importPath string) Decision
+	// DecideSymbol checks the use of the identifier name of the package importPath.
+	DecideSymbol(importPath, name string) Decision
+	// DecideCall checks a call of the function, method or builtin name.
+	DecideCall(name string) Decision
+	// DecideImportForm checks an import written in the given form.
+	DecideImportForm(form config.ImportForm) Decision
+	// DecideExport checks a type of the package typePackage referenced by the exported API.
+	DecideExport(typePackage string) Decision
+	// DecideTransitive checks a package reached through other imports.
+	DecideTransitive(importPath string) Decision
+}
+
+// Decision is the outcome of checking a single import against a group's rules.
+// Rule, Severity, Message and Docs describe the rule responsible for a denial and are empty
+// when the import is allowed.
+type Decision struct {
+	Allowed  bool
+	Rule     string
+	Severity config.Severity
+	Message  string
+	Docs     string
 }
 
 type Group interface {
 	Name() string
+	// Source is the config file that declared the group.
+	Source() string
 	MatchPath(path string) bool
 	GetDependencyChecker(path string) DependencyChecker
+	// DecideImporter checks an import of one of the group's packages by the package
+	// importer, which private groups restrict to their own packages and friend groups.
+	DecideImporter(importer string) Decision
+	// DecideUnsafe checks a use of feature by the group's packages, which only groups
+	// designated for it allow.
+	DecideUnsafe(feature config.UnsafeFeature) Decision
+	Limits() Limits
+}
+
+// Limits bounds the fan-out and fan-in of a group. Zero values mean no limit.
+type Limits struct {
+	MaxImports   int
+	MaxImporters int
+	// Severity applies to violations of the limits.
+	Severity config.Severity
 }
@@ -1,6 +1,7 @@
 package groups
 
 import (
+	"fmt"
 	"path"
 	"strings"
 
@@ -9,20 +10,26 @@ import (
 
 type ImportRule interface {
 	Allows(fromPackage, toImport string) bool
+	String() string
 }
 
 type globImportRule struct {
-	g glob.Glob
+	pattern string
+	g       glob.Glob
 }
 
 func NewGlobImportRule(pattern string) ImportRule {
-	return &globImportRule{g: glob.MustCompile(pattern)}
+	return &globImportRule{pattern: pattern, g: glob.MustCompile(pattern)}
 }
 
 func (m *globImportRule) Allows(_, toImport string) bool {
 	return m.g.Match(toImport)
 }
 
+func (m *globImportRule) String() string {
+	return fmt.Sprintf("pattern %q", m.pattern)
+}
+
 type relativeImportRule struct {
 	relativePath string
 }
@@ -35,6 +42,10 @@ func (m *relativeImportRule) Allows(fromPackage, toImport string) bool {
 	return path.Join(fromPackage, m.relativePath) == toImport
 }
 
+func (m *relativeImportRule) String() string {
+	return fmt.Sprintf("relative %q", m.relativePath)
+}
+
 type groupImportRule struct {
 	grp Group
 }
@@ -47,6 +58,10 @@ func (m *groupImportRule) Allows(_, toImport string) bool {
 	return m.grp.MatchPath(toImport)
 }
 
+func (m *groupImportRule) String() string {
+	return fmt.Sprintf("group %q", m.grp.Name())
+}
+
 type subPackageImportRule struct {
 }
 
@@ -58,3 +73,29 @@ func (m *subPackageImportRule) Allows(fromPackage, toImport string) bool {
 	return strings.HasPrefix(toImport, fromPackage+"/")
 }
 
+func (m *subPackageImportRule) String() string {
+	return "subPackages"
+}
+
+type layerImportRule struct {
+	layer  string
+	target Group
+	upward bool
+}
+
+// NewLayerImportRule matches imports of target from a package in layer. It describes the match
+// as a layer violation rather than as a plain deny rule.
+func NewLayerImportRule(layer string, target Group, upward bool) ImportRule {
+	return &layerImportRule{layer: layer, target: target, upward: upward}
+}
+
+func (m *layerImportRule) Allows(_, toImport string) bool {
+	return m.target.MatchPath(toImport)
+}
+
+func (m *layerImportRule) String() string {
+	if m.upward {
+		return fmt.Sprintf("layer violation: layer %q imports higher layer %q", m.layer, m.target.Name())
+	}
+	return fmt.Sprintf("layer violation: layer %q skips layers to import %q", m.layer, m.target.Name())
+}
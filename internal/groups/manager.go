@@ -3,6 +3,7 @@ package groups
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/coderhyme/arch-lint/internal/config"
 )
@@ -20,9 +21,14 @@ func NewGroupManager(cfg *config.Config) (GroupManager, error) {
 
 	// Phase 1: Create placeholder groups (just paths, no dependencies)
 	for name, groupConfig := range cfg.Groups {
+		pathMatchers, err := buildPathMatchers(groupConfig.Paths)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build group %s: %w", name, err)
+		}
 		gm.groups[name] = &groupWithRules{
 			name:         name,
-			pathMatchers: buildPathMatchers(groupConfig.Paths),
+			source:       groupConfig.Source,
+			pathMatchers: pathMatchers,
 		}
 	}
 
@@ -54,3 +60,14 @@ func (gm *groupManager) GetGroup(ctx context.Context, name string) (Group, error
 	}
 	return nil, fmt.Errorf("group %s not found", name)
 }
+
+func (gm *groupManager) ListGroups(ctx context.Context) ([]Group, error) {
+	result := make([]Group, 0, len(gm.groups))
+	for _, group := range gm.groups {
+		result = append(result, group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name() < result[j].Name()
+	})
+	return result, nil
+}
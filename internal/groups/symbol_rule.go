@@ -0,0 +1,68 @@
+package groups
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/coderhyme/arch-lint/internal/config"
+	"github.com/gobwas/glob"
+)
+
+// symbolRule matches uses of identifiers of imported packages. Its pattern is an import
+// path glob and an identifier glob joined by the last dot, such as "internal/legacy.DB"
+// or "internal/domain/**.New*".
+type symbolRule struct {
+	pattern string
+	pkg     glob.Glob
+	name    glob.Glob
+	message config.RuleMessage
+}
+
+func newSymbolRule(pattern string, message config.RuleMessage) (*symbolRule, error) {
+	dot := strings.LastIndex(pattern, ".")
+	if dot <= strings.LastIndex(pattern, "/") || dot == len(pattern)-1 {
+		return nil, fmt.Errorf("symbol %q must have the form <import path>.<identifier>", pattern)
+	}
+
+	pkg, err := glob.Compile(pattern[:dot])
+	if err != nil {
+		return nil, fmt.Errorf("invalid symbol %q: %w", pattern, err)
+	}
+	name, err := glob.Compile(pattern[dot+1:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid symbol %q: %w", pattern, err)
+	}
+
+	return &symbolRule{pattern: pattern, pkg: pkg, name: name, message: message}, nil
+}
+
+// Allows reports whether toImport is the package of the rule's identifiers, which makes
+// an allow rule listing symbols allow the import itself.
+func (r *symbolRule) Allows(_, toImport string) bool {
+	return r.pkg.Match(toImport)
+}
+
+func (r *symbolRule) Matches(importPath, name string) bool {
+	return r.pkg.Match(importPath) && r.name.Match(name)
+}
+
+func (r *symbolRule) String() string {
+	return fmt.Sprintf("symbol %q", r.pattern)
+}
+
+func buildSymbolRules(rule *config.DependencyRule) ([]*symbolRule, error) {
+	var rules []*symbolRule
+	for _, pattern := range rule.Symbols {
+		message := config.RuleMessage{Message: rule.Message, Docs: rule.Docs}
+		if msg, ok := rule.PatternMessages[pattern]; ok {
+			message = msg
+		}
+
+		sr, err := newSymbolRule(pattern, message)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, sr)
+	}
+	return rules, nil
+}
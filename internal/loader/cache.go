@@ -0,0 +1,93 @@
+package loader
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// cacheVersion is part of every cache key. Bump it whenever the cached data or the way
+// imports are extracted changes, so stale entries are never read.
+const cacheVersion = "imports-v2"
+
+// Cache stores the imports of parsed files on disk, keyed by a hash of the file contents.
+type Cache struct {
+	dir string
+}
+
+// DefaultCacheDir returns the arch-lint directory inside the user cache dir.
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "arch-lint"), nil
+}
+
+func OpenCache(dir string) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	return &Cache{dir: dir}, nil
+}
+
+func (c *Cache) key(content []byte) string {
+	h := sha256.New()
+	h.Write([]byte(cacheVersion))
+	h.Write([]byte{0})
+	h.Write(content)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key[:2], key)
+}
+
+func (c *Cache) get(key string) ([]Import, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	var imports []Import
+	if err := json.Unmarshal(data, &imports); err != nil {
+		return nil, false
+	}
+	return imports, true
+}
+
+// put stores imports under key. Failing to write the cache only costs a re-parse on the
+// next run, so errors are not reported.
+func (c *Cache) put(key string, imports []Import) {
+	data, err := json.Marshal(imports)
+	if err != nil {
+		return
+	}
+
+	path := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), key+".tmp*")
+	if err != nil {
+		return
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+// Clean removes every cached entry.
+func (c *Cache) Clean() error {
+	return os.RemoveAll(c.dir)
+}
@@ -0,0 +1,113 @@
+package loader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Index keeps the imports of every file in a module so that packages can be updated one file
+// at a time instead of re-walking the whole tree.
+type Index struct {
+	rootPath   string
+	modulePath string
+	cache      *Cache
+	files      map[string][]Import
+}
+
+// NewIndex loads every file below rootPath like Load does.
+func NewIndex(ctx context.Context, rootPath string, opts Options) (*Index, []error) {
+	rt, modulePath, errs := load(ctx, rootPath, opts)
+	if rt == nil {
+		return nil, errs
+	}
+
+	return &Index{
+		rootPath:   rootPath,
+		modulePath: modulePath,
+		cache:      opts.Cache,
+		files:      rt.files,
+	}, errs
+}
+
+func (ix *Index) ModulePath() string {
+	return ix.modulePath
+}
+
+// Update re-reads the file at path, which may be absolute or relative to the module root.
+// Files that no longer exist are dropped. It reports whether path is a file the loader tracks.
+func (ix *Index) Update(path string) (bool, error) {
+	relPath := path
+	if filepath.IsAbs(path) {
+		rel, err := filepath.Rel(ix.rootPath, path)
+		if err != nil {
+			return false, fmt.Errorf("failed to get relative path for %s: %w", path, err)
+		}
+		relPath = rel
+	}
+	if !isSourceFile(relPath) || skippedDir(filepath.Dir(relPath)) {
+		return false, nil
+	}
+
+	absPath := filepath.Join(ix.rootPath, relPath)
+	if _, err := os.Stat(absPath); errors.Is(err, fs.ErrNotExist) {
+		delete(ix.files, relPath)
+		return true, nil
+	}
+
+	rt := &repoTraverser{rootPath: ix.rootPath, cache: ix.cache}
+	imports, err := rt.fileImports(absPath)
+	if err != nil {
+		delete(ix.files, relPath)
+		return true, fmt.Errorf("failed to extract imports from %s: %w", absPath, err)
+	}
+	ix.files[relPath] = imports
+	return true, nil
+}
+
+// Packages aggregates the file imports into the per-package form returned by Load.
+func (ix *Index) Packages() map[string]map[string]struct{} {
+	packages := make(map[string]map[string]struct{})
+	for relPath, imports := range ix.files {
+		dir := filepath.Dir(relPath)
+		if _, exists := packages[dir]; !exists {
+			packages[dir] = make(map[string]struct{})
+		}
+		for _, imp := range imports {
+			packages[dir][imp.Path] = struct{}{}
+		}
+	}
+	return packages
+}
+
+// Files returns the import declarations of every file, keyed by its path relative to the
+// module root.
+func (ix *Index) Files() map[string][]Import {
+	files := make(map[string][]Import, len(ix.files))
+	for relPath, imports := range ix.files {
+		files[relPath] = imports
+	}
+	return files
+}
+
+func isSourceFile(path string) bool {
+	return strings.HasSuffix(path, ".go") && !strings.HasSuffix(path, "_test.go")
+}
+
+// skippedDir reports whether a directory relative to the module root is one the loader
+// does not descend into.
+func skippedDir(relDir string) bool {
+	if relDir == "." {
+		return false
+	}
+	for _, part := range strings.Split(filepath.ToSlash(relDir), "/") {
+		if strings.HasPrefix(part, ".") || part == "vendor" {
+			return true
+		}
+	}
+	return false
+}
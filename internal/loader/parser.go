@@ -1,6 +1,7 @@
 package loader
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"go/parser"
@@ -8,37 +9,86 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 
 	"golang.org/x/mod/modfile"
+	"golang.org/x/sync/errgroup"
 )
 
+type Options struct {
+	// Jobs bounds the number of files parsed concurrently. Values below 1 use GOMAXPROCS.
+	Jobs int
+	// Cache, when set, is used to skip parsing files whose contents did not change.
+	Cache *Cache
+}
+
+// Import is an import declaration of a file.
+type Import struct {
+	Path string `json:"path"`
+	// Name is "." for dot imports, "_" for blank imports, the alias of renamed imports and
+	// empty otherwise.
+	Name string `json:"name,omitempty"`
+	Line int    `json:"line"`
+}
+
 type repoTraverser struct {
 	rootPath       string
+	jobs           int
+	cache          *Cache
 	errs           []error
 	packageImports map[string]map[string]struct{}
+	// files holds the imports of every parsed file, keyed by its path relative to rootPath.
+	files map[string][]Import
+}
+
+func Load(ctx context.Context, rootPath string, opts Options) (modulePath string, packages map[string]map[string]struct{}, errs []error) {
+	rt, modulePath, errs := load(ctx, rootPath, opts)
+	if rt == nil {
+		return "", nil, errs
+	}
+	return modulePath, rt.packageImports, errs
 }
 
-func Load(rootPath string) (modulePath string, packages map[string]map[string]struct{}, errs []error) {
+func load(ctx context.Context, rootPath string, opts Options) (*repoTraverser, string, []error) {
+	jobs := opts.Jobs
+	if jobs < 1 {
+		jobs = runtime.GOMAXPROCS(0)
+	}
+
 	rt := &repoTraverser{
 		rootPath:       rootPath,
+		jobs:           jobs,
+		cache:          opts.Cache,
 		packageImports: make(map[string]map[string]struct{}),
+		files:          make(map[string][]Import),
+	}
+
+	if err := rt.traverse(ctx); err != nil {
+		return nil, "", append(rt.errs, err)
 	}
 
-	rt.traverse()
+	modulePath, err := readModulePath(rootPath)
+	if err != nil {
+		return nil, "", append(rt.errs, err)
+	}
 
+	return rt, modulePath, rt.errs
+}
+
+func readModulePath(rootPath string) (string, error) {
 	goModPath := filepath.Join(rootPath, "go.mod")
 	file, err := os.ReadFile(goModPath)
 	if err != nil {
-		return "", nil, append(rt.errs, fmt.Errorf("failed to read go.mod: %w", err))
+		return "", fmt.Errorf("failed to read go.mod: %w", err)
 	}
 
 	mod, err := modfile.ParseLax(goModPath, file, nil)
 	if err != nil {
-		return "", nil, append(rt.errs, fmt.Errorf("failed to parse go.mod: %w", err))
+		return "", fmt.Errorf("failed to parse go.mod: %w", err)
 	}
 
-	return mod.Module.Mod.Path, rt.packageImports, rt.errs
+	return mod.Module.Mod.Path, nil
 }
 
 var errDoNotSkip = errors.New("do not skip")
@@ -51,33 +101,50 @@ func (*repoTraverser) shouldSkip(path string, d fs.DirEntry) error {
 		return nil
 	}
 
-	if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+	if !isSourceFile(path) {
 		return nil
 	}
 	return errDoNotSkip
 }
 
-func (rt *repoTraverser) updateImports(path string, imports []string) {
-	dir := filepath.Dir(path)
-	relDir, err := filepath.Rel(rt.rootPath, dir)
+func (rt *repoTraverser) updateImports(path string, imports []Import) {
+	relPath, err := filepath.Rel(rt.rootPath, path)
 	if err != nil {
-		rt.errs = append(rt.errs, fmt.Errorf("failed to get relative path for %s: %w", dir, err))
+		rt.errs = append(rt.errs, fmt.Errorf("failed to get relative path for %s: %w", path, err))
 		return
 	}
+	rt.files[relPath] = imports
+
+	relDir := filepath.Dir(relPath)
 
 	if _, exists := rt.packageImports[relDir]; !exists {
 		rt.packageImports[relDir] = make(map[string]struct{})
 	}
 
 	for _, imp := range imports {
-		rt.packageImports[relDir][imp] = struct{}{}
+		rt.packageImports[relDir][imp.Path] = struct{}{}
 	}
 }
 
-func (rt *repoTraverser) traverse() {
-	_ = filepath.WalkDir(rt.rootPath, func(path string, d fs.DirEntry, err error) error {
+// walkEntry is a file to parse or an error met while walking, kept in walk order.
+type walkEntry struct {
+	path    string
+	walkErr error
+	imports []Import
+	err     error
+}
+
+// traverse walks the tree, parses files on up to rt.jobs workers and then merges the results
+// in walk order, so the outcome does not depend on the number of workers.
+func (rt *repoTraverser) traverse(ctx context.Context) error {
+	var entries []*walkEntry
+	err := filepath.WalkDir(rt.rootPath, func(path string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
 		if err != nil {
-			rt.errs = append(rt.errs, err)
+			entries = append(entries, &walkEntry{walkErr: err})
 			return nil
 		}
 
@@ -85,27 +152,88 @@ func (rt *repoTraverser) traverse() {
 			return err
 		}
 
-		imports, err := extractImports(path)
-		if err != nil {
-			rt.errs = append(rt.errs, fmt.Errorf("failed to extract imports from %s: %w", path, err))
+		entries = append(entries, &walkEntry{path: path})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(rt.jobs)
+	for _, entry := range entries {
+		if entry.walkErr != nil {
+			continue
+		}
+		g.Go(func() error {
+			if err := gctx.Err(); err != nil {
+				return err
+			}
+			entry.imports, entry.err = rt.fileImports(entry.path)
 			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		switch {
+		case entry.walkErr != nil:
+			rt.errs = append(rt.errs, entry.walkErr)
+		case entry.err != nil:
+			rt.errs = append(rt.errs, fmt.Errorf("failed to extract imports from %s: %w", entry.path, entry.err))
+		default:
+			rt.updateImports(entry.path, entry.imports)
 		}
+	}
 
-		rt.updateImports(path, imports)
+	return nil
+}
 
-		return nil
-	})
+func (rt *repoTraverser) fileImports(filename string) ([]Import, error) {
+	if rt.cache == nil {
+		return extractImports(filename, nil)
+	}
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	key := rt.cache.key(content)
+	if imports, ok := rt.cache.get(key); ok {
+		return imports, nil
+	}
+
+	imports, err := extractImports(filename, content)
+	if err != nil {
+		return nil, err
+	}
+	rt.cache.put(key, imports)
+	return imports, nil
 }
 
-func extractImports(filename string) ([]string, error) {
-	node, err := parser.ParseFile(token.NewFileSet(), filename, nil, parser.ImportsOnly)
+// extractImports parses the imports of filename, reading it from disk when src is nil.
+func extractImports(filename string, src []byte) ([]Import, error) {
+	var source any
+	if src != nil {
+		source = src
+	}
+
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, filename, source, parser.ImportsOnly)
 	if err != nil {
 		return nil, err
 	}
 
-	var imports []string
-	for _, imp := range node.Imports {
-		imports = append(imports, strings.Trim(imp.Path.Value, `"`))
+	var imports []Import
+	for _, spec := range node.Imports {
+		imp := Import{Path: strings.Trim(spec.Path.Value, `"`), Line: fset.Position(spec.Pos()).Line}
+		if spec.Name != nil {
+			imp.Name = spec.Name.Name
+		}
+		imports = append(imports, imp)
 	}
 
 	return imports, nil
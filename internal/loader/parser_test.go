@@ -0,0 +1,292 @@
+package loader
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type LoaderSuite struct {
+	suite.Suite
+	root string
+}
+
+func TestLoaderSuite(t *testing.T) {
+	suite.Run(t, new(LoaderSuite))
+}
+
+func (s *LoaderSuite) SetupTest() {
+	s.root = s.T().TempDir()
+	s.write("go.mod", "module github.com/example/app\n\ngo 1.22\n")
+}
+
+func (s *LoaderSuite) write(name, content string) {
+	path := filepath.Join(s.root, name)
+	s.Require().NoError(os.MkdirAll(filepath.Dir(path), 0o755))
+	s.Require().NoError(os.WriteFile(path, []byte(content), 0o644))
+}
+
+func (s *LoaderSuite) TestLoad_ConcurrentMatchesSequential() {
+	// given
+	for i := 0; i < 50; i++ {
+		s.write(fmt.Sprintf("internal/pkg%d/f%d.go", i%10, i), fmt.Sprintf(
+			"package pkg\n\nimport (\n\t\"fmt\"\n\t\"github.com/example/app/internal/dep%d\"\n)\n", i))
+	}
+	s.write("internal/broken/a.go", "package broken\n\nimport (\n")
+	s.write("internal/pkg1/a_test.go", "package pkg\n\nimport \"testing\"\n")
+	s.write("vendor/lib/a.go", "package lib\n\nimport \"os\"\n")
+
+	// when
+	modulePath, sequential, seqErrs := Load(context.Background(), s.root, Options{Jobs: 1})
+	_, concurrent, conErrs := Load(context.Background(), s.root, Options{Jobs: 8})
+
+	// then
+	assert.Equal(s.T(), "github.com/example/app", modulePath)
+	assert.Equal(s.T(), sequential, concurrent)
+	assert.Equal(s.T(), seqErrs, conErrs)
+	assert.Len(s.T(), seqErrs, 1)
+	assert.Contains(s.T(), sequential[filepath.Join("internal", "pkg3")], "github.com/example/app/internal/dep13")
+	assert.NotContains(s.T(), sequential[filepath.Join("internal", "pkg1")], "testing")
+	assert.NotContains(s.T(), sequential, filepath.Join("vendor", "lib"))
+}
+
+func (s *LoaderSuite) TestLoad_Cancelled() {
+	// given
+	s.write("main.go", "package main\n")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// when
+	modulePath, packages, errs := Load(ctx, s.root, Options{})
+
+	// then
+	assert.Empty(s.T(), modulePath)
+	assert.Nil(s.T(), packages)
+	s.Require().NotEmpty(errs)
+	assert.ErrorIs(s.T(), errs[len(errs)-1], context.Canceled)
+}
+
+func (s *LoaderSuite) TestLoad_CacheInvalidatedByContent() {
+	// given
+	cache, err := OpenCache(filepath.Join(s.T().TempDir(), "cache"))
+	s.Require().NoError(err)
+	s.write("internal/a/a.go", "package a\n\nimport \"fmt\"\n")
+
+	_, first, errs := Load(context.Background(), s.root, Options{Cache: cache})
+	s.Require().Empty(errs)
+
+	// when
+	s.write("internal/a/a.go", "package a\n\nimport \"os\"\n")
+	_, second, errs := Load(context.Background(), s.root, Options{Cache: cache})
+
+	// then
+	assert.Empty(s.T(), errs)
+	assert.Contains(s.T(), first[filepath.Join("internal", "a")], "fmt")
+	assert.Equal(s.T(), map[string]struct{}{"os": {}}, second[filepath.Join("internal", "a")])
+	_, cached := cache.get(cache.key([]byte("package a\n\nimport \"fmt\"\n")))
+	assert.True(s.T(), cached)
+}
+
+func (s *LoaderSuite) TestIndex_UpdatesSingleFiles() {
+	// given
+	s.write("internal/a/a.go", "package a\n\nimport \"fmt\"\n")
+	s.write("internal/a/b.go", "package a\n\nimport \"os\"\n")
+	index, errs := NewIndex(context.Background(), s.root, Options{})
+	s.Require().Empty(errs)
+
+	// when
+	s.write("internal/a/a.go", "package a\n\nimport \"strings\"\n")
+	changed, err := index.Update(filepath.Join(s.root, "internal/a/a.go"))
+	s.Require().NoError(err)
+	s.Require().NoError(os.Remove(filepath.Join(s.root, "internal/a/b.go")))
+	removed, err := index.Update(filepath.Join(s.root, "internal/a/b.go"))
+	s.Require().NoError(err)
+	ignored, err := index.Update(filepath.Join(s.root, "internal/a/a_test.go"))
+	s.Require().NoError(err)
+
+	// then
+	assert.True(s.T(), changed)
+	assert.True(s.T(), removed)
+	assert.False(s.T(), ignored)
+	assert.Equal(s.T(), "github.com/example/app", index.ModulePath())
+	assert.Equal(s.T(), map[string]struct{}{"strings": {}}, index.Packages()[filepath.Join("internal", "a")])
+}
+
+func (s *LoaderSuite) TestIndex_KeepsImportNames() {
+	// given
+	cache, err := OpenCache(filepath.Join(s.T().TempDir(), "cache"))
+	s.Require().NoError(err)
+	s.write("internal/a/a.go", "package a\n\nimport (\n\t\"fmt\"\n\t_ \"embed\"\n\t. \"strings\"\n\tu \"unsafe\"\n)\n")
+
+	// when
+	first, errs := NewIndex(context.Background(), s.root, Options{Cache: cache})
+	s.Require().Empty(errs)
+	cached, errs := NewIndex(context.Background(), s.root, Options{Cache: cache})
+	s.Require().Empty(errs)
+
+	// then
+	expected := []Import{
+		{Path: "fmt", Line: 4},
+		{Path: "embed", Name: "_", Line: 5},
+		{Path: "strings", Name: ".", Line: 6},
+		{Path: "unsafe", Name: "u", Line: 7},
+	}
+	assert.Equal(s.T(), expected, first.Files()[filepath.Join("internal", "a", "a.go")])
+	assert.Equal(s.T(), expected, cached.Files()[filepath.Join("internal", "a", "a.go")])
+}
+
+func (s *LoaderSuite) TestFilterPackages_GoStylePatterns() {
+	// given
+	packages := map[string]map[string]struct{}{
+		".":                                 {},
+		filepath.Join("internal", "domain"): {},
+		filepath.Join("internal", "domain", "user"): {},
+		filepath.Join("internal", "domainx"):        {},
+		filepath.Join("cmd", "app"):                 {},
+	}
+
+	// when
+	filtered, err := FilterPackages(packages, []string{"internal/domain/...", "cmd/app"})
+
+	// then
+	s.Require().NoError(err)
+	assert.Len(s.T(), filtered, 3)
+	assert.Contains(s.T(), filtered, filepath.Join("internal", "domain"))
+	assert.Contains(s.T(), filtered, filepath.Join("internal", "domain", "user"))
+	assert.Contains(s.T(), filtered, filepath.Join("cmd", "app"))
+
+	all, err := FilterPackages(packages, []string{"..."})
+	s.Require().NoError(err)
+	assert.Len(s.T(), all, len(packages))
+
+	_, err = FilterPackages(packages, []string{"../other/..."})
+	assert.Error(s.T(), err)
+}
+
+func (s *LoaderSuite) TestLoadSymbolUses_ResolvesPackageNames() {
+	// given
+	s.write("internal/legacy/store/db.go", "package legacydb\n\ntype DB struct{}\n")
+	s.write("internal/api/handler.go", `package api
+
+import (
+	"fmt"
+
+	"github.com/example/app/internal/legacy/store"
+	dom "github.com/example/app/internal/domain"
+	"go.yaml.in/yaml/v4"
+)
+
+func Handle(fmt string) {
+	var db legacydb.DB
+	_ = dom.User{}
+	_, _ = yaml.Marshal(db)
+	_ = fmt.Sprint
+}
+`)
+
+	// when
+	uses, errs := LoadSymbolUses(context.Background(), s.root, "github.com/example/app", []string{"internal/api"}, Options{})
+
+	// then
+	assert.Empty(s.T(), errs)
+	var found []string
+	for _, use := range uses {
+		assert.Equal(s.T(), "internal/api", use.Package)
+		found = append(found, fmt.Sprintf("%s.%s@%d", use.Import, use.Name, use.Pos.Line))
+	}
+	assert.Equal(s.T(), []string{
+		"github.com/example/app/internal/legacy/store.DB@12",
+		"github.com/example/app/internal/domain.User@13",
+		"go.yaml.in/yaml/v4.Marshal@14",
+	}, found)
+}
+
+func (s *LoaderSuite) TestLoadUnsafeUses_DirectivesAndUnsafeIdentifiers() {
+	// given
+	s.write("internal/rt/rt.go", "package rt\n\nimport (\n\t_ \"unsafe\"\n\tu \"unsafe\"\n)\n\n//go:linkname nanotime runtime.nanotime\nfunc nanotime() int64\n\nvar a = u.Pointer(nil)\nvar b = u.Pointer(nil)\nvar c = u.Sizeof(a)\n\n// go:linkname in prose is not a directive\n")
+	s.write("internal/plain/p.go", "package plain\n\nfunc unsafe() {}\n")
+
+	// when
+	uses, errs := LoadUnsafeUses(context.Background(), s.root, []string{filepath.Join("internal", "rt"), filepath.Join("internal", "plain")}, Options{})
+
+	// then
+	assert.Empty(s.T(), errs)
+	s.Require().Len(uses, 3)
+	assert.Equal(s.T(), "//go:linkname nanotime runtime.nanotime", uses[0].Directive)
+	assert.Equal(s.T(), "internal/rt/rt.go", uses[0].Pos.Filename)
+	assert.Equal(s.T(), 8, uses[0].Pos.Line)
+	assert.Equal(s.T(), "Pointer", uses[1].Name)
+	assert.Equal(s.T(), 11, uses[1].Pos.Line)
+	assert.Equal(s.T(), "Sizeof", uses[2].Name)
+	assert.Equal(s.T(), "internal/rt", uses[2].Package)
+}
+
+func (s *LoaderSuite) TestLoadUnsafeUses_NamedImportBeforeBlankImport() {
+	// given
+	s.write("internal/rt/rt.go", "package rt\n\nimport (\n\tu \"unsafe\"\n\t_ \"unsafe\"\n)\n\nvar a = u.Pointer(nil)\n")
+
+	// when
+	uses, errs := LoadUnsafeUses(context.Background(), s.root, []string{filepath.Join("internal", "rt")}, Options{})
+
+	// then
+	assert.Empty(s.T(), errs)
+	s.Require().Len(uses, 1)
+	assert.Equal(s.T(), "Pointer", uses[0].Name)
+	assert.Equal(s.T(), 8, uses[0].Pos.Line)
+}
+
+func (s *LoaderSuite) TestLoadUnsafeUses_DotImport() {
+	// given
+	s.write("internal/rt/rt.go", `package rt
+
+import . "unsafe"
+
+var a = Pointer(nil)
+
+func size(Sizeof func(any) int) int {
+	return Sizeof(a)
+}
+
+var b = Add(a, 1)
+`)
+
+	// when
+	uses, errs := LoadUnsafeUses(context.Background(), s.root, []string{filepath.Join("internal", "rt")}, Options{})
+
+	// then
+	assert.Empty(s.T(), errs)
+	var found []string
+	for _, use := range uses {
+		found = append(found, fmt.Sprintf("%s@%d", use.Name, use.Pos.Line))
+	}
+	assert.Equal(s.T(), []string{"Pointer@5", "Add@11"}, found)
+}
+
+func (s *LoaderSuite) TestPackageNamer_LoadResolvesNamesOutsideModule() {
+	// given
+	s.write("go.mod", "module github.com/example/app\n\ngo 1.22\n\nrequire k8s.io/api v0.0.0\n\nreplace k8s.io/api => ./third_party/api\n")
+	s.write("third_party/api/go.mod", "module k8s.io/api\n\ngo 1.22\n")
+	s.write("third_party/api/core/v1/types.go", "package v1\n\ntype Pod struct{}\n")
+	s.write("internal/legacy/store/db.go", "package legacydb\n")
+	namer := NewPackageNamer(s.root, "github.com/example/app")
+
+	// when
+	err := namer.Load(context.Background(), []string{"k8s.io/api/core/v1", "github.com/example/app/internal/legacy/store"})
+
+	// then
+	s.Require().NoError(err)
+	name, known := namer.Resolve("k8s.io/api/core/v1")
+	assert.Equal(s.T(), "v1", name)
+	assert.True(s.T(), known)
+	name, known = namer.Resolve("github.com/example/app/internal/legacy/store")
+	assert.Equal(s.T(), "legacydb", name)
+	assert.True(s.T(), known)
+	name, known = namer.Resolve("gopkg.in/yaml.v3")
+	assert.Equal(s.T(), "yaml", name)
+	assert.False(s.T(), known)
+}
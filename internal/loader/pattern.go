@@ -0,0 +1,51 @@
+package loader
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// FilterPackages keeps the packages matching at least one of the Go-style package patterns,
+// such as "internal/..." or "cmd/arch-lint". Patterns are slash-separated and relative to the
+// module root; "..." matches any string, and a trailing "/..." also matches the directory itself.
+func FilterPackages(packages map[string]map[string]struct{}, patterns []string) (map[string]map[string]struct{}, error) {
+	var matchers []*regexp.Regexp
+	for _, pattern := range patterns {
+		re, err := compilePackagePattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, re)
+	}
+
+	filtered := make(map[string]map[string]struct{})
+	for pkg, imports := range packages {
+		slashPkg := filepath.ToSlash(pkg)
+		for _, re := range matchers {
+			if re.MatchString(slashPkg) {
+				filtered[pkg] = imports
+				break
+			}
+		}
+	}
+	return filtered, nil
+}
+
+func compilePackagePattern(pattern string) (*regexp.Regexp, error) {
+	if pattern == ".." || strings.HasPrefix(pattern, "../") {
+		return nil, fmt.Errorf("package pattern %s is outside the module", pattern)
+	}
+
+	if pattern == "..." {
+		return regexp.MustCompile(`^.*$`), nil
+	}
+
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.ReplaceAll(expr, `\.\.\.`, `.*`)
+	if strings.HasSuffix(expr, `/.*`) {
+		expr = strings.TrimSuffix(expr, `/.*`) + `(/.*)?`
+	}
+	return regexp.Compile("^" + expr + "$")
+}
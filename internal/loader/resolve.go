@@ -0,0 +1,87 @@
+package loader
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+)
+
+// ImportUse is an identifier that refers to an import of its file: the package name of a
+// qualified identifier such as domain.User, or an identifier of a dot import.
+type ImportUse struct {
+	Spec *ast.ImportSpec
+	// Ident is the package name of a qualified identifier, or the dot-imported identifier.
+	Ident *ast.Ident
+	// Name is the identifier of the imported package, such as "User".
+	Name string
+}
+
+// ImportUses returns the identifiers of file that refer to its imports, in source order.
+// The file is type-checked on its own, with imported packages named by name but otherwise
+// empty, so local declarations shadow package names as they do when compiling. Identifiers
+// of dot imports are only resolved for package unsafe, the one package known without
+// loading it.
+func ImportUses(fset *token.FileSet, file *ast.File, name func(importPath string) string) []ImportUse {
+	info := &types.Info{
+		Defs:      make(map[*ast.Ident]types.Object),
+		Uses:      make(map[*ast.Ident]types.Object),
+		Implicits: make(map[ast.Node]types.Object),
+	}
+	conf := types.Config{
+		Importer: stubImporter(name),
+		// Declarations of the package's other files and members of imported packages are
+		// missing, so errors are expected and only the resolved identifiers matter.
+		Error: func(error) {},
+	}
+	_, _ = conf.Check(file.Name.Name, fset, []*ast.File{file}, info)
+
+	specs := make(map[types.Object]*ast.ImportSpec)
+	var dotUnsafe *ast.ImportSpec
+	for _, spec := range file.Imports {
+		obj := info.Implicits[spec]
+		if spec.Name != nil {
+			obj = info.Defs[spec.Name]
+		}
+		if obj != nil {
+			specs[obj] = spec
+		}
+		if path, err := strconv.Unquote(spec.Path.Value); err == nil && path == "unsafe" && spec.Name != nil && spec.Name.Name == "." && dotUnsafe == nil {
+			dotUnsafe = spec
+		}
+	}
+
+	var uses []ImportUse
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			ident, ok := n.X.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			if spec, ok := specs[info.Uses[ident]]; ok {
+				uses = append(uses, ImportUse{Spec: spec, Ident: ident, Name: n.Sel.Name})
+				return false
+			}
+		case *ast.Ident:
+			if obj := info.Uses[n]; dotUnsafe != nil && obj != nil && obj.Pkg() == types.Unsafe {
+				uses = append(uses, ImportUse{Spec: dotUnsafe, Ident: n, Name: n.Name})
+			}
+		}
+		return true
+	})
+	return uses
+}
+
+// stubImporter imports package unsafe, and any other package as an empty package named by
+// the function.
+type stubImporter func(importPath string) string
+
+func (si stubImporter) Import(path string) (*types.Package, error) {
+	if path == "unsafe" {
+		return types.Unsafe, nil
+	}
+	pkg := types.NewPackage(path, si(path))
+	pkg.MarkComplete()
+	return pkg, nil
+}
@@ -0,0 +1,224 @@
+package loader
+
+import (
+	"context"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/tools/go/packages"
+)
+
+// SymbolUse is a reference to an identifier of an imported package, such as domain.User.
+type SymbolUse struct {
+	// Package is the using package, relative to the module root.
+	Package string
+	// Import is the full import path of the package the identifier belongs to.
+	Import string
+	Name   string
+	// Pos is the position of the selector, with a file name relative to the module root.
+	Pos token.Position
+}
+
+// LoadSymbolUses parses the files of packages below rootPath, given relative to rootPath
+// like the packages returned by Load, and returns every qualified identifier that refers
+// to an imported package. Unlike Load it parses whole files, so callers only use it when
+// rules need it. Dot imports are only followed for package unsafe, and the name of a package
+// outside the module is assumed from its import path.
+func LoadSymbolUses(ctx context.Context, rootPath, modulePath string, packages []string, opts Options) ([]SymbolUse, []error) {
+	jobs := opts.Jobs
+	if jobs < 1 {
+		jobs = runtime.GOMAXPROCS(0)
+	}
+
+	files, errs := packageFiles(rootPath, packages)
+	sp := &symbolParser{rootPath: rootPath, names: NewPackageNamer(rootPath, modulePath)}
+	uses := make([][]SymbolUse, len(files))
+	fileErrs := make([]error, len(files))
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(jobs)
+	for i, file := range files {
+		g.Go(func() error {
+			if err := gctx.Err(); err != nil {
+				return err
+			}
+			uses[i], fileErrs[i] = sp.fileUses(file)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, append(errs, err)
+	}
+
+	var result []SymbolUse
+	for i, file := range files {
+		if fileErrs[i] != nil {
+			errs = append(errs, fmt.Errorf("failed to extract symbols from %s: %w", file, fileErrs[i]))
+			continue
+		}
+		result = append(result, uses[i]...)
+	}
+	return result, errs
+}
+
+// packageFiles lists the source files of packages, relative to rootPath.
+func packageFiles(rootPath string, packages []string) ([]string, []error) {
+	var files []string
+	var errs []error
+	for _, pkg := range packages {
+		entries, err := os.ReadDir(filepath.Join(rootPath, pkg))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to read package %s: %w", pkg, err))
+			continue
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() && isSourceFile(entry.Name()) {
+				files = append(files, filepath.Join(pkg, entry.Name()))
+			}
+		}
+	}
+	sort.Strings(files)
+	return files, errs
+}
+
+type symbolParser struct {
+	rootPath string
+	names    *PackageNamer
+}
+
+// fileUses parses the file at relPath, relative to the module root.
+func (sp *symbolParser) fileUses(relPath string) ([]SymbolUse, error) {
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, filepath.Join(sp.rootPath, relPath), nil, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg := filepath.ToSlash(filepath.Dir(relPath))
+	var uses []SymbolUse
+	for _, use := range ImportUses(fset, node, sp.names.Name) {
+		pos := fset.Position(use.Ident.Pos())
+		pos.Filename = filepath.ToSlash(relPath)
+		uses = append(uses, SymbolUse{Package: pkg, Import: strings.Trim(use.Spec.Path.Value, `"`), Name: use.Name, Pos: pos})
+	}
+	return uses, nil
+}
+
+// PackageNamer resolves the names files refer to imported packages by when the imports are
+// not renamed. It is safe for concurrent use.
+type PackageNamer struct {
+	rootPath   string
+	modulePath string
+
+	mu sync.Mutex
+	// names caches the package names of module packages, and of other packages resolved by
+	// Load, keyed by import path.
+	names map[string]string
+}
+
+func NewPackageNamer(rootPath, modulePath string) *PackageNamer {
+	return &PackageNamer{rootPath: rootPath, modulePath: modulePath, names: make(map[string]string)}
+}
+
+// Load resolves the package names of the importPaths outside the module with go list, which
+// needs their modules to be downloaded. Packages it cannot resolve keep assumed names.
+func (pn *PackageNamer) Load(ctx context.Context, importPaths []string) error {
+	var external []string
+	for _, importPath := range importPaths {
+		if !pn.inModule(importPath) && importPath != "C" {
+			external = append(external, importPath)
+		}
+	}
+	if len(external) == 0 {
+		return nil
+	}
+
+	loaded, err := packages.Load(&packages.Config{Context: ctx, Dir: pn.rootPath, Mode: packages.NeedName}, external...)
+	if err != nil {
+		return fmt.Errorf("failed to resolve package names: %w", err)
+	}
+
+	pn.mu.Lock()
+	defer pn.mu.Unlock()
+	var errs []string
+	for _, pkg := range loaded {
+		if len(pkg.Errors) > 0 || pkg.Name == "" {
+			errs = append(errs, pkg.PkgPath)
+			continue
+		}
+		pn.names[pkg.PkgPath] = pkg.Name
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to resolve package names of %s", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// Name returns the package name of importPath. Module packages are named by their package
+// clause and packages resolved by Load by their real name; the name of any other package is
+// assumed from its import path.
+func (pn *PackageNamer) Name(importPath string) string {
+	name, _ := pn.Resolve(importPath)
+	return name
+}
+
+// Resolve returns the package name of importPath like Name, and whether the name is known
+// rather than assumed.
+func (pn *PackageNamer) Resolve(importPath string) (string, bool) {
+	pn.mu.Lock()
+	defer pn.mu.Unlock()
+	if name, ok := pn.names[importPath]; ok {
+		return name, true
+	}
+	if !pn.inModule(importPath) {
+		return assumedPackageName(importPath), false
+	}
+
+	dir := filepath.Join(pn.rootPath, filepath.FromSlash(strings.TrimPrefix(strings.TrimPrefix(importPath, pn.modulePath), "/")))
+	if entries, err := os.ReadDir(dir); err == nil {
+		for _, entry := range entries {
+			if entry.IsDir() || !isSourceFile(entry.Name()) {
+				continue
+			}
+			file, err := parser.ParseFile(token.NewFileSet(), filepath.Join(dir, entry.Name()), nil, parser.PackageClauseOnly)
+			if err == nil {
+				pn.names[importPath] = file.Name.Name
+				return file.Name.Name, true
+			}
+		}
+	}
+	return assumedPackageName(importPath), false
+}
+
+func (pn *PackageNamer) inModule(importPath string) bool {
+	return importPath == pn.modulePath || strings.HasPrefix(importPath, pn.modulePath+"/")
+}
+
+// assumedPackageName guesses the package name of importPath from its last element,
+// skipping major version suffixes such as "v2" and a "go-" prefix.
+func assumedPackageName(importPath string) string {
+	base := path.Base(importPath)
+	if len(base) > 1 && base[0] == 'v' {
+		if _, err := strconv.Atoi(base[1:]); err == nil {
+			base = path.Base(path.Dir(importPath))
+		}
+	}
+	base = strings.TrimPrefix(base, "go-")
+	if i := strings.IndexFunc(base, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	}); i >= 0 {
+		base = base[:i]
+	}
+	return base
+}
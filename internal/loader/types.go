@@ -0,0 +1,101 @@
+package loader
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"runtime"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// TypeCounts counts the type declarations of a package. Aliases are not counted.
+type TypeCounts struct {
+	Interfaces int
+	Concrete   int
+}
+
+// Abstractness is the share of interfaces among the declared types, or 0 for a package
+// without types.
+func (c TypeCounts) Abstractness() float64 {
+	total := c.Interfaces + c.Concrete
+	if total == 0 {
+		return 0
+	}
+	return float64(c.Interfaces) / float64(total)
+}
+
+// CountTypes parses the files of packages below rootPath, given relative to rootPath like
+// the packages returned by Load, and counts their interface and concrete type declarations.
+func CountTypes(ctx context.Context, rootPath string, packages []string, opts Options) (map[string]TypeCounts, []error) {
+	jobs := opts.Jobs
+	if jobs < 1 {
+		jobs = runtime.GOMAXPROCS(0)
+	}
+
+	files, errs := packageFiles(rootPath, packages)
+	counts := make([]TypeCounts, len(files))
+	fileErrs := make([]error, len(files))
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(jobs)
+	for i, file := range files {
+		g.Go(func() error {
+			if err := gctx.Err(); err != nil {
+				return err
+			}
+			counts[i], fileErrs[i] = fileTypeCounts(filepath.Join(rootPath, file))
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, append(errs, err)
+	}
+
+	result := make(map[string]TypeCounts, len(packages))
+	for _, pkg := range packages {
+		result[filepath.ToSlash(pkg)] = TypeCounts{}
+	}
+	for i, file := range files {
+		if fileErrs[i] != nil {
+			errs = append(errs, fmt.Errorf("failed to count types in %s: %w", file, fileErrs[i]))
+			continue
+		}
+		pkg := filepath.ToSlash(filepath.Dir(file))
+		c := result[pkg]
+		c.Interfaces += counts[i].Interfaces
+		c.Concrete += counts[i].Concrete
+		result[pkg] = c
+	}
+	return result, errs
+}
+
+func fileTypeCounts(path string) (TypeCounts, error) {
+	node, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return TypeCounts{}, err
+	}
+
+	var counts TypeCounts
+	for _, decl := range node.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if ts.Assign.IsValid() {
+				continue
+			}
+			if _, ok := ts.Type.(*ast.InterfaceType); ok {
+				counts.Interfaces++
+			} else {
+				counts.Concrete++
+			}
+		}
+	}
+	return counts, nil
+}
@@ -0,0 +1,105 @@
+package loader
+
+import (
+	"context"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// linknamePrefix starts the directives that bind a local name to a symbol of another
+// package, bypassing its export rules.
+const linknamePrefix = "//go:linkname"
+
+// UnsafeUse is a //go:linkname directive or a use of an identifier of package unsafe.
+type UnsafeUse struct {
+	// Package is the using package, relative to the module root.
+	Package string
+	// Directive is the text of a //go:linkname directive, and Name the used identifier of
+	// package unsafe, such as "Pointer". Exactly one of them is set.
+	Directive string
+	Name      string
+	// Pos is the position of the directive or the first use of Name in its file, with a file
+	// name relative to the module root.
+	Pos token.Position
+}
+
+// LoadUnsafeUses parses the files of packages below rootPath, given relative to rootPath
+// like the packages returned by Load, and returns their //go:linkname directives and uses of
+// package unsafe, including identifiers of a dot import of unsafe.
+func LoadUnsafeUses(ctx context.Context, rootPath string, packages []string, opts Options) ([]UnsafeUse, []error) {
+	jobs := opts.Jobs
+	if jobs < 1 {
+		jobs = runtime.GOMAXPROCS(0)
+	}
+
+	files, errs := packageFiles(rootPath, packages)
+	uses := make([][]UnsafeUse, len(files))
+	fileErrs := make([]error, len(files))
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(jobs)
+	for i, file := range files {
+		g.Go(func() error {
+			if err := gctx.Err(); err != nil {
+				return err
+			}
+			uses[i], fileErrs[i] = fileUnsafeUses(rootPath, file)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, append(errs, err)
+	}
+
+	var result []UnsafeUse
+	for i, file := range files {
+		if fileErrs[i] != nil {
+			errs = append(errs, fmt.Errorf("failed to extract unsafe uses from %s: %w", file, fileErrs[i]))
+			continue
+		}
+		result = append(result, uses[i]...)
+	}
+	return result, errs
+}
+
+// fileUnsafeUses parses the file at relPath, relative to rootPath.
+func fileUnsafeUses(rootPath, relPath string) ([]UnsafeUse, error) {
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, filepath.Join(rootPath, relPath), nil, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg := filepath.ToSlash(filepath.Dir(relPath))
+	position := func(pos token.Pos) token.Position {
+		p := fset.Position(pos)
+		p.Filename = filepath.ToSlash(relPath)
+		return p
+	}
+
+	var uses []UnsafeUse
+	for _, group := range node.Comments {
+		for _, c := range group.List {
+			if c.Text == linknamePrefix || strings.HasPrefix(c.Text, linknamePrefix+" ") {
+				uses = append(uses, UnsafeUse{Package: pkg, Directive: c.Text, Pos: position(c.Pos())})
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, use := range ImportUses(fset, node, assumedPackageName) {
+		if path, err := strconv.Unquote(use.Spec.Path.Value); err != nil || path != "unsafe" || seen[use.Name] {
+			continue
+		}
+		seen[use.Name] = true
+		uses = append(uses, UnsafeUse{Package: pkg, Name: use.Name, Pos: position(use.Ident.Pos())})
+	}
+	return uses, nil
+}
@@ -0,0 +1,185 @@
+// Package metrics computes the coupling metrics of packages and groups: afferent and
+// efferent coupling, instability, abstractness and distance from the main sequence.
+package metrics
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/coderhyme/arch-lint/internal/checker"
+	"github.com/coderhyme/arch-lint/internal/config"
+	"github.com/coderhyme/arch-lint/internal/groups"
+	"github.com/coderhyme/arch-lint/internal/loader"
+)
+
+// Metrics are the coupling metrics of a package or group. Couplings count module packages
+// only.
+type Metrics struct {
+	// Ca (afferent coupling) is the number of packages depending on the package, and Ce
+	// (efferent coupling) the number of packages it depends on.
+	Ca int `json:"ca"`
+	Ce int `json:"ce"`
+	// Interfaces and Concrete count the declared types.
+	Interfaces int `json:"interfaces"`
+	Concrete   int `json:"concrete"`
+	// Instability is Ce / (Ca + Ce), Abstractness the share of interfaces among the
+	// declared types, and Distance |A + I - 1|, the distance from the main sequence.
+	Instability  float64 `json:"instability"`
+	Abstractness float64 `json:"abstractness"`
+	Distance     float64 `json:"distance"`
+}
+
+func newMetrics(ca, ce int, types loader.TypeCounts) Metrics {
+	m := Metrics{
+		Ca:           ca,
+		Ce:           ce,
+		Interfaces:   types.Interfaces,
+		Concrete:     types.Concrete,
+		Abstractness: types.Abstractness(),
+	}
+	if ca+ce > 0 {
+		m.Instability = float64(ce) / float64(ca+ce)
+	}
+	m.Distance = math.Abs(m.Abstractness + m.Instability - 1)
+	return m
+}
+
+type PackageMetrics struct {
+	Package string   `json:"package"`
+	Groups  []string `json:"groups,omitempty"`
+	Metrics
+}
+
+type GroupMetrics struct {
+	Group    string `json:"group"`
+	Packages int    `json:"packages"`
+	Metrics
+}
+
+// Breach is a group metric beyond the threshold configured for it.
+type Breach struct {
+	Group     string  `json:"group"`
+	Metric    string  `json:"metric"`
+	Value     float64 `json:"value"`
+	Threshold float64 `json:"threshold"`
+}
+
+type Report struct {
+	Packages []PackageMetrics `json:"packages"`
+	Groups   []GroupMetrics   `json:"groups"`
+	Breaches []Breach         `json:"breaches,omitempty"`
+}
+
+// Compute computes the metrics of every package of graph, which holds the imports of every
+// package of the module, and of every group with packages. A group's couplings count the
+// packages outside the group. types holds the type counts of the packages. Group metrics
+// beyond the thresholds configured for the group in cfg are reported as breaches.
+func Compute(ctx context.Context, cfg *config.Config, modulePath string, graph map[string]map[string]struct{}, types map[string]loader.TypeCounts, manager groups.GroupManager) (*Report, error) {
+	dependencies := make(map[string]map[string]struct{})
+	dependents := make(map[string]map[string]struct{})
+	for pkgPath, imports := range graph {
+		dependencies[pkgPath] = make(map[string]struct{})
+		for imp := range imports {
+			rel, internal := checker.StripModulePrefix(modulePath, imp)
+			if !internal || rel == pkgPath {
+				continue
+			}
+			dependencies[pkgPath][rel] = struct{}{}
+			if dependents[rel] == nil {
+				dependents[rel] = make(map[string]struct{})
+			}
+			dependents[rel][pkgPath] = struct{}{}
+		}
+	}
+
+	allGroups, err := manager.ListGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{}
+	members := make(map[string][]string)
+	for _, pkgPath := range sortedKeys(graph) {
+		pm := PackageMetrics{
+			Package: pkgPath,
+			Metrics: newMetrics(len(dependents[pkgPath]), len(dependencies[pkgPath]), types[pkgPath]),
+		}
+		for _, grp := range allGroups {
+			if grp.MatchPath(pkgPath) {
+				pm.Groups = append(pm.Groups, grp.Name())
+				members[grp.Name()] = append(members[grp.Name()], pkgPath)
+			}
+		}
+		report.Packages = append(report.Packages, pm)
+	}
+
+	for _, grp := range allGroups {
+		pkgs := members[grp.Name()]
+		if len(pkgs) == 0 {
+			continue
+		}
+
+		inGroup := make(map[string]bool, len(pkgs))
+		for _, pkgPath := range pkgs {
+			inGroup[pkgPath] = true
+		}
+
+		afferent := make(map[string]struct{})
+		efferent := make(map[string]struct{})
+		var counts loader.TypeCounts
+		for _, pkgPath := range pkgs {
+			for dep := range dependents[pkgPath] {
+				if !inGroup[dep] {
+					afferent[dep] = struct{}{}
+				}
+			}
+			for dep := range dependencies[pkgPath] {
+				if !inGroup[dep] {
+					efferent[dep] = struct{}{}
+				}
+			}
+			counts.Interfaces += types[pkgPath].Interfaces
+			counts.Concrete += types[pkgPath].Concrete
+		}
+
+		gm := GroupMetrics{
+			Group:    grp.Name(),
+			Packages: len(pkgs),
+			Metrics:  newMetrics(len(afferent), len(efferent), counts),
+		}
+		report.Groups = append(report.Groups, gm)
+		if groupCfg := cfg.Groups[grp.Name()]; groupCfg != nil {
+			report.Breaches = append(report.Breaches, breaches(grp.Name(), groupCfg.Metrics, gm.Metrics)...)
+		}
+	}
+
+	return report, nil
+}
+
+func breaches(group string, thresholds *config.MetricThresholds, m Metrics) []Breach {
+	if thresholds == nil {
+		return nil
+	}
+
+	var result []Breach
+	if t := thresholds.MaxInstability; t != nil && m.Instability > *t {
+		result = append(result, Breach{Group: group, Metric: "instability", Value: m.Instability, Threshold: *t})
+	}
+	if t := thresholds.MinAbstractness; t != nil && m.Abstractness < *t {
+		result = append(result, Breach{Group: group, Metric: "abstractness", Value: m.Abstractness, Threshold: *t})
+	}
+	if t := thresholds.MaxDistance; t != nil && m.Distance > *t {
+		result = append(result, Breach{Group: group, Metric: "distance", Value: m.Distance, Threshold: *t})
+	}
+	return result
+}
+
+func sortedKeys(m map[string]map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/coderhyme/arch-lint/internal/config"
+	"github.com/coderhyme/arch-lint/internal/groups"
+	"github.com/coderhyme/arch-lint/internal/loader"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type MetricsSuite struct {
+	suite.Suite
+}
+
+func TestMetricsSuite(t *testing.T) {
+	suite.Run(t, new(MetricsSuite))
+}
+
+func (s *MetricsSuite) TestCompute_PackageAndGroupMetrics() {
+	// given
+	maxInstability := 0.4
+	cfg := &config.Config{
+		Version: 1,
+		Groups: map[string]*config.Group{
+			"domain": {
+				Paths:   config.PathConfigs{{Dir: "internal/domain/**"}},
+				Metrics: &config.MetricThresholds{MaxInstability: &maxInstability},
+			},
+			"service": {
+				Paths: config.PathConfigs{{Dir: "internal/service/**"}},
+			},
+		},
+	}
+	manager, err := groups.NewGroupManager(cfg)
+	s.Require().NoError(err)
+
+	graph := map[string]map[string]struct{}{
+		"internal/domain/user": {
+			"github.com/example/app/internal/domain/shared": {},
+			"github.com/example/app/internal/util":          {},
+			"fmt":                                           {},
+		},
+		"internal/domain/shared": {},
+		"internal/service/user": {
+			"github.com/example/app/internal/domain/user": {},
+		},
+		"internal/util": {},
+	}
+	types := map[string]loader.TypeCounts{
+		"internal/domain/user":   {Interfaces: 1, Concrete: 1},
+		"internal/domain/shared": {Interfaces: 1, Concrete: 3},
+	}
+
+	// when
+	report, err := Compute(context.Background(), cfg, "github.com/example/app", graph, types, manager)
+
+	// then
+	s.Require().NoError(err)
+	byPackage := map[string]PackageMetrics{}
+	for _, pm := range report.Packages {
+		byPackage[pm.Package] = pm
+	}
+	user := byPackage["internal/domain/user"]
+	assert.Equal(s.T(), 1, user.Ca)
+	assert.Equal(s.T(), 2, user.Ce)
+	assert.InDelta(s.T(), 2.0/3, user.Instability, 1e-9)
+	assert.InDelta(s.T(), 0.5, user.Abstractness, 1e-9)
+	assert.InDelta(s.T(), 1.0/6, user.Distance, 1e-9)
+	assert.Equal(s.T(), []string{"domain"}, user.Groups)
+
+	s.Require().Len(report.Groups, 2)
+	domain := report.Groups[0]
+	assert.Equal(s.T(), "domain", domain.Group)
+	assert.Equal(s.T(), 2, domain.Packages)
+	assert.Equal(s.T(), 1, domain.Ca)
+	assert.Equal(s.T(), 1, domain.Ce)
+	assert.InDelta(s.T(), 2.0/6, domain.Abstractness, 1e-9)
+
+	assert.Equal(s.T(), []Breach{{Group: "domain", Metric: "instability", Value: 0.5, Threshold: 0.4}}, report.Breaches)
+}
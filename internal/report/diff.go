@@ -0,0 +1,227 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/coderhyme/arch-lint/internal/checker"
+)
+
+type GroupEdge struct {
+	From  string
+	To    string
+	Count int
+}
+
+type PackageEdge struct {
+	From string
+	To   string
+}
+
+// Diff describes how the architecture changed between two checked trees.
+type Diff struct {
+	AddedGroups         []string
+	RemovedGroups       []string
+	AddedGroupEdges     []GroupEdge
+	RemovedGroupEdges   []GroupEdge
+	AddedPackageEdges   []PackageEdge
+	RemovedPackageEdges []PackageEdge
+	NewViolations       []checker.Violation
+	ResolvedViolations  []checker.Violation
+}
+
+// Compare reports the differences from base to head. Group edges count as added or removed
+// when they appear or disappear entirely; changes in their count alone are not reported.
+func Compare(base, head *Model) *Diff {
+	d := &Diff{}
+
+	d.AddedGroups = missing(head.Groups, base.Groups)
+	d.RemovedGroups = missing(base.Groups, head.Groups)
+	d.AddedGroupEdges = missingGroupEdges(head, base)
+	d.RemovedGroupEdges = missingGroupEdges(base, head)
+	d.AddedPackageEdges = missingPackageEdges(head, base)
+	d.RemovedPackageEdges = missingPackageEdges(base, head)
+	d.NewViolations = missingViolations(head, base)
+	d.ResolvedViolations = missingViolations(base, head)
+
+	return d
+}
+
+func (d *Diff) Empty() bool {
+	return len(d.AddedGroups) == 0 && len(d.RemovedGroups) == 0 &&
+		len(d.AddedGroupEdges) == 0 && len(d.RemovedGroupEdges) == 0 &&
+		len(d.AddedPackageEdges) == 0 && len(d.RemovedPackageEdges) == 0 &&
+		len(d.NewViolations) == 0 && len(d.ResolvedViolations) == 0
+}
+
+func missing(from, in []string) []string {
+	present := make(map[string]bool, len(in))
+	for _, s := range in {
+		present[s] = true
+	}
+
+	var result []string
+	for _, s := range from {
+		if !present[s] {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func missingGroupEdges(from, in *Model) []GroupEdge {
+	var result []GroupEdge
+	for _, src := range from.Groups {
+		for _, dst := range from.Groups {
+			count := from.Edges[src][dst]
+			if count > 0 && in.Edges[src][dst] == 0 {
+				result = append(result, GroupEdge{From: src, To: dst, Count: count})
+			}
+		}
+	}
+	return result
+}
+
+func missingPackageEdges(from, in *Model) []PackageEdge {
+	present := make(map[PackageEdge]bool)
+	for pkg, imports := range in.Imports {
+		for _, imp := range imports {
+			present[PackageEdge{From: pkg, To: imp}] = true
+		}
+	}
+
+	var result []PackageEdge
+	for _, pkg := range sortedKeys(from.Imports) {
+		for _, imp := range from.Imports[pkg] {
+			if edge := (PackageEdge{From: pkg, To: imp}); !present[edge] {
+				result = append(result, edge)
+			}
+		}
+	}
+	return result
+}
+
+func missingViolations(from, in *Model) []checker.Violation {
+	present := make(map[violationKey]bool)
+	for _, v := range in.flatViolations() {
+		present[keyOf(v)] = true
+	}
+
+	var result []checker.Violation
+	for _, v := range from.flatViolations() {
+		if !present[keyOf(v)] {
+			result = append(result, v)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Package != result[j].Package {
+			return result[i].Package < result[j].Package
+		}
+		return result[i].Import < result[j].Import
+	})
+	return result
+}
+
+// violationKey identifies a violation across revisions. A limit is keyed by its group and
+// rule rather than its count and top offender, so a group that stays over a limit is the same
+// violation.
+type violationKey struct {
+	kind                     checker.Kind
+	pkg, target, group, rule string
+}
+
+func keyOf(v checker.Violation) violationKey {
+	if v.Kind == checker.KindLimit {
+		return violationKey{kind: v.Kind, group: v.GroupName, rule: v.Rule}
+	}
+	return violationKey{kind: v.Kind, pkg: v.Package, target: v.Target(), group: v.GroupName}
+}
+
+func (m *Model) flatViolations() []checker.Violation {
+	var result []checker.Violation
+	for _, gv := range m.Violations {
+		for _, rv := range gv.Rules {
+			result = append(result, rv.Violations...)
+		}
+	}
+	return result
+}
+
+// WriteDiffText renders the diff as a plain-text summary.
+func WriteDiffText(w io.Writer, d *Diff) error {
+	if d.Empty() {
+		_, err := fmt.Fprintln(w, "No architecture changes")
+		return err
+	}
+
+	ew := &errWriter{w: w}
+	section := func(title string, n int) bool {
+		if n == 0 {
+			return false
+		}
+		ew.printf("%s (%d):\n", title, n)
+		return true
+	}
+
+	if section("New groups", len(d.AddedGroups)) {
+		for _, g := range d.AddedGroups {
+			ew.printf("  + %s\n", g)
+		}
+	}
+	if section("Removed groups", len(d.RemovedGroups)) {
+		for _, g := range d.RemovedGroups {
+			ew.printf("  - %s\n", g)
+		}
+	}
+	if section("Added group edges", len(d.AddedGroupEdges)) {
+		for _, e := range d.AddedGroupEdges {
+			ew.printf("  + %s -> %s (%d)\n", e.From, e.To, e.Count)
+		}
+	}
+	if section("Removed group edges", len(d.RemovedGroupEdges)) {
+		for _, e := range d.RemovedGroupEdges {
+			ew.printf("  - %s -> %s (%d)\n", e.From, e.To, e.Count)
+		}
+	}
+	if section("Added package edges", len(d.AddedPackageEdges)) {
+		for _, e := range d.AddedPackageEdges {
+			ew.printf("  + %s -> %s\n", e.From, e.To)
+		}
+	}
+	if section("Removed package edges", len(d.RemovedPackageEdges)) {
+		for _, e := range d.RemovedPackageEdges {
+			ew.printf("  - %s -> %s\n", e.From, e.To)
+		}
+	}
+	if section("New violations", len(d.NewViolations)) {
+		for _, v := range d.NewViolations {
+			ew.printf("  + %s -> %s (%s, %s: %s)\n", v.Package, v.Target(), v.Severity, v.DeniedBy(), v.Rule)
+			if v.Chain != "" {
+				ew.printf("      via %s\n", v.Chain)
+			}
+			if explanation := v.Explanation(); explanation != "" {
+				ew.printf("      %s\n", explanation)
+			}
+		}
+	}
+	if section("Resolved violations", len(d.ResolvedViolations)) {
+		for _, v := range d.ResolvedViolations {
+			ew.printf("  - %s -> %s (%s, %s: %s)\n", v.Package, v.Target(), v.Severity, v.DeniedBy(), v.Rule)
+		}
+	}
+
+	return ew.err
+}
+
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) printf(format string, args ...any) {
+	if ew.err != nil {
+		return
+	}
+	_, ew.err = fmt.Fprintf(ew.w, format, args...)
+}
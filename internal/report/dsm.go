@@ -0,0 +1,203 @@
+package report
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/coderhyme/arch-lint/internal/groups"
+)
+
+type CellStatus string
+
+const (
+	CellUnknown   CellStatus = ""
+	CellAllowed   CellStatus = "allowed"
+	CellDenied    CellStatus = "denied"
+	CellViolation CellStatus = "violation"
+)
+
+type DSMCell struct {
+	Count  int
+	Status CellStatus
+	// Notes holds the messages and docs of the rules violated by the cell's edges.
+	Notes []string
+}
+
+// DSM is a design structure matrix: Cells[i][j] describes imports from Groups[i] into Groups[j].
+type DSM struct {
+	Groups []string
+	Cells  [][]DSMCell
+}
+
+// NewDSM classifies every cell of the model's group matrix. Cells with violating edges are
+// violations; otherwise a cell is allowed when some package of the row group may import
+// some package of the column group according to the row group's rules and, for a private
+// column group, the column group's importers, and denied when none may. Cells whose groups
+// have no loaded packages are left unknown.
+func NewDSM(ctx context.Context, m *Model, manager groups.GroupManager) (*DSM, error) {
+	dsm := &DSM{Groups: m.Groups}
+
+	allGroups := make(map[string]groups.Group, len(m.Groups))
+	for _, name := range m.Groups {
+		grp, err := manager.GetGroup(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		allGroups[name] = grp
+	}
+
+	for _, from := range m.Groups {
+
+		row := make([]DSMCell, 0, len(m.Groups))
+		for _, to := range m.Groups {
+			cell := DSMCell{Count: m.Edges[from][to]}
+			switch {
+			case m.Violating[from][to] > 0:
+				cell.Status = CellViolation
+				cell.Notes = m.Explanations[from][to]
+			case cell.Count > 0:
+				cell.Status = CellAllowed
+			default:
+				cell.Status = groupStatus(allGroups[from], allGroups[to], m.GroupPackages[from], m.GroupPackages[to])
+			}
+			row = append(row, cell)
+		}
+		dsm.Cells = append(dsm.Cells, row)
+	}
+
+	return dsm, nil
+}
+
+func groupStatus(from, to groups.Group, fromPackages, toPackages []string) CellStatus {
+	if len(fromPackages) == 0 || len(toPackages) == 0 {
+		return CellUnknown
+	}
+
+	for _, pkg := range fromPackages {
+		checker := from.GetDependencyChecker(pkg)
+		for _, target := range toPackages {
+			if target != pkg && checker.CanDependOn(target) && to.DecideImporter(pkg).Allowed {
+				return CellAllowed
+			}
+		}
+	}
+	if len(fromPackages) == 1 && len(toPackages) == 1 && fromPackages[0] == toPackages[0] {
+		return CellUnknown
+	}
+	return CellDenied
+}
+
+func (c DSMCell) text() string {
+	switch c.Status {
+	case CellViolation:
+		return strconv.Itoa(c.Count) + "!"
+	case CellDenied:
+		return "x"
+	}
+	if c.Count == 0 {
+		if c.Status == CellUnknown {
+			return "-"
+		}
+		return "."
+	}
+	return strconv.Itoa(c.Count)
+}
+
+// WriteDSMText renders the matrix as an aligned table. Rows import columns.
+func WriteDSMText(w io.Writer, dsm *DSM) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	header := make([]string, 0, len(dsm.Groups)+1)
+	header = append(header, "")
+	for i := range dsm.Groups {
+		header = append(header, strconv.Itoa(i+1))
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t")+"\t")
+
+	for i, name := range dsm.Groups {
+		cells := make([]string, 0, len(dsm.Groups)+1)
+		cells = append(cells, fmt.Sprintf("%d %s", i+1, name))
+		for _, cell := range dsm.Cells[i] {
+			cells = append(cells, cell.text())
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t")+"\t")
+	}
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintln(w, "\nRows import columns. N = allowed edges, N! = edges with violations, x = denied, . = no edges, - = unknown"); err != nil {
+		return err
+	}
+
+	notes := dsm.notes()
+	if len(notes) == 0 {
+		return nil
+	}
+	if _, err := fmt.Fprintln(w, "\nViolated rules:"); err != nil {
+		return err
+	}
+	for _, note := range notes {
+		if _, err := fmt.Fprintf(w, "  %s -> %s: %s\n", note.from, note.to, note.text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type dsmNote struct {
+	from, to, text string
+}
+
+// notes lists the notes of every cell, row by row.
+func (dsm *DSM) notes() []dsmNote {
+	var notes []dsmNote
+	for i, from := range dsm.Groups {
+		for j, cell := range dsm.Cells[i] {
+			for _, text := range cell.Notes {
+				notes = append(notes, dsmNote{from: from, to: dsm.Groups[j], text: text})
+			}
+		}
+	}
+	return notes
+}
+
+// WriteDSMCSV renders the matrix as CSV with cells formatted as "count:status". The last
+// column lists the notes of the row's violating cells as "group: note", separated by "; ".
+func WriteDSMCSV(w io.Writer, dsm *DSM) error {
+	cw := csv.NewWriter(w)
+
+	header := append([]string{"group"}, dsm.Groups...)
+	header = append(header, "notes")
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for i, name := range dsm.Groups {
+		record := []string{name}
+		var notes []string
+		for j, cell := range dsm.Cells[i] {
+			status := cell.Status
+			if status == CellUnknown {
+				status = "unknown"
+			}
+			record = append(record, fmt.Sprintf("%d:%s", cell.Count, status))
+			for _, note := range cell.Notes {
+				notes = append(notes, dsm.Groups[j]+": "+note)
+			}
+		}
+		record = append(record, strings.Join(notes, "; "))
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
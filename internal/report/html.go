@@ -0,0 +1,117 @@
+package report
+
+import (
+	"html/template"
+	"io"
+)
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>arch-lint report: {{.ModulePath}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #222; }
+h1 { font-size: 1.6em; }
+h2 { border-bottom: 1px solid #ddd; padding-bottom: .2em; margin-top: 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: .3em .6em; text-align: left; }
+td.count { text-align: right; }
+td.zero { color: #bbb; }
+td.self { background: #f4f4f4; }
+code { font-family: Menlo, Consolas, monospace; font-size: .95em; }
+.summary span { margin-right: 2em; }
+.violation { color: #b00020; }
+.severity-error { color: #b00020; }
+.severity-warning { color: #b26a00; }
+.severity-info { color: #555; }
+</style>
+</head>
+<body>
+<h1>Architecture report: <code>{{.ModulePath}}</code></h1>
+<p class="summary">
+<span>{{.PackagesCount}} packages</span>
+<span>{{len .Groups}} groups</span>
+<span>{{len .Unassigned}} unassigned packages</span>
+</p>
+
+<h2>Group dependencies</h2>
+<p>Rows import columns; cells count package-level import edges.</p>
+{{- $groups := .Groups}}
+<table>
+<tr><th></th>{{range $groups}}<th>{{.}}</th>{{end}}</tr>
+{{- range .Matrix}}
+{{- $from := .Group}}
+<tr><th>{{$from}}</th>
+{{- range $i, $count := .Cells}}
+<td class="count{{if eq $count 0}} zero{{end}}{{if eq (index $groups $i) $from}} self{{end}}">{{$count}}</td>
+{{- end}}</tr>
+{{- end}}
+</table>
+
+<h2>Violations</h2>
+{{- if .Violations}}
+{{- range .Violations}}
+<h3>{{if .Group}}Group <code>{{.Group}}</code>{{else}}{{.Title}}{{end}}{{if .Source}} <small>declared in <code>{{.Source}}</code></small>{{end}}</h3>
+{{- range .Rules}}
+<h4 class="violation">{{.Rule}} ({{len .Violations}})</h4>
+<table>
+<tr><th>Severity</th><th>Package</th><th>Import</th><th>Message</th></tr>
+{{- range .Violations}}
+<tr><td class="severity-{{.Severity}}">{{.Severity}}</td><td><code>{{.Package}}</code></td><td><code>{{.Target}}</code>{{if .Pos.IsValid}} <small>{{.Pos}}</small>{{end}}{{if .Offenders}} <small>top: {{.Offenders}}</small>{{end}}</td><td>{{.Message}}{{if .Docs}} <a href="{{.Docs}}">docs</a>{{end}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- end}}
+{{- else}}
+<p>No violations found.</p>
+{{- end}}
+
+<h2>Exceptions</h2>
+<p>{{.Suppressed}} violation(s) suppressed by exceptions.</p>
+{{- if .ExpiredExceptions}}
+<h3 class="violation">Expired ({{len .ExpiredExceptions}})</h3>
+{{template "exceptions" .ExpiredExceptions}}
+{{- end}}
+{{- if .UnusedExceptions}}
+<h3>Unused ({{len .UnusedExceptions}})</h3>
+{{template "exceptions" .UnusedExceptions}}
+{{- end}}
+
+<h2>Unassigned packages</h2>
+{{- if .Unassigned}}
+<ul>
+{{- range .Unassigned}}
+<li><code>{{.}}</code></li>
+{{- end}}
+</ul>
+{{- else}}
+<p>Every package belongs to a group.</p>
+{{- end}}
+
+<h2>Packages by group</h2>
+{{- $packages := .GroupPackages}}
+{{- range $groups}}
+<h3>{{.}} ({{len (index $packages .)}})</h3>
+<ul>
+{{- range index $packages .}}
+<li><code>{{.}}</code></li>
+{{- end}}
+</ul>
+{{- end}}
+</body>
+</html>
+{{- define "exceptions"}}
+<table>
+<tr><th>Package</th><th>Import</th><th>Expires</th><th>Owner</th><th>Reason</th></tr>
+{{- range .}}
+<tr><td><code>{{.Package}}</code></td><td><code>{{.Import}}</code></td><td>{{.Expires}}</td><td>{{.Owner}}</td><td>{{.Reason}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+`))
+
+// WriteHTML renders the model as a single self-contained HTML page.
+func WriteHTML(w io.Writer, m *Model) error {
+	return htmlTemplate.Execute(w, m)
+}
@@ -0,0 +1,229 @@
+package report
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/coderhyme/arch-lint/internal/checker"
+	"github.com/coderhyme/arch-lint/internal/config"
+	"github.com/coderhyme/arch-lint/internal/groups"
+)
+
+// Model is the group-level view of a checked module shared by the report writers.
+type Model struct {
+	ModulePath    string
+	PackagesCount int
+	Groups        []string
+	// Edges counts package-level imports between groups, keyed by importing and imported group.
+	Edges map[string]map[string]int
+	// Violating counts the edges in Edges that were reported as violations.
+	Violating map[string]map[string]int
+	// Explanations lists the distinct messages and docs of the rules violated by the edges
+	// in Violating.
+	Explanations  map[string]map[string][]string
+	GroupPackages map[string][]string
+	// Imports lists the module-internal imports of every package, relative to the module.
+	Imports    map[string][]string
+	Unassigned []string
+	Violations []GroupViolations
+	// Suppressed counts the violations covered by exceptions.
+	Suppressed        int
+	ExpiredExceptions []config.Exception
+	UnusedExceptions  []config.Exception
+}
+
+type GroupViolations struct {
+	Group string
+	// Title names violations that belong to no group: those of aliases, and unsafe uses of
+	// packages without a group.
+	Title string
+	// Source is the config file that declared the group.
+	Source string
+	Rules  []RuleViolations
+}
+
+type RuleViolations struct {
+	Rule       string
+	Violations []checker.Violation
+}
+
+type MatrixRow struct {
+	Group string
+	Cells []int
+}
+
+func Build(ctx context.Context, modulePath string, packageImports map[string]map[string]struct{}, result *checker.Result, manager groups.GroupManager) (*Model, error) {
+	allGroups, err := manager.ListGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Model{
+		ModulePath:        modulePath,
+		PackagesCount:     result.PackagesCount,
+		Suppressed:        len(result.Suppressed),
+		ExpiredExceptions: result.ExpiredExceptions,
+		UnusedExceptions:  result.UnusedExceptions,
+		Edges:             make(map[string]map[string]int),
+		Violating:         make(map[string]map[string]int),
+		Explanations:      make(map[string]map[string][]string),
+		GroupPackages:     make(map[string][]string),
+		Imports:           make(map[string][]string),
+	}
+	for _, grp := range allGroups {
+		m.Groups = append(m.Groups, grp.Name())
+		m.Edges[grp.Name()] = make(map[string]int)
+		m.Violating[grp.Name()] = make(map[string]int)
+		m.Explanations[grp.Name()] = make(map[string][]string)
+	}
+
+	for pkgPath, imports := range packageImports {
+		fromGroups, err := manager.GetGroups(ctx, pkgPath)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(fromGroups) == 0 {
+			m.Unassigned = append(m.Unassigned, pkgPath)
+		}
+		for _, grp := range fromGroups {
+			m.GroupPackages[grp.Name()] = append(m.GroupPackages[grp.Name()], pkgPath)
+		}
+
+		m.Imports[pkgPath] = []string{}
+		for imp := range imports {
+			relImport, ok := checker.StripModulePrefix(modulePath, imp)
+			if !ok {
+				continue
+			}
+			m.Imports[pkgPath] = append(m.Imports[pkgPath], relImport)
+
+			toGroups, err := manager.GetGroups(ctx, relImport)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, from := range fromGroups {
+				for _, to := range toGroups {
+					m.Edges[from.Name()][to.Name()]++
+				}
+			}
+		}
+	}
+
+	for _, v := range result.Violations {
+		// Only group rules deny edges between groups; violations without a group, of aliases
+		// and of unsafe uses in packages without a group, deny none.
+		if v.Import == "" || v.GroupName == "" {
+			continue
+		}
+		fromNames := []string{v.GroupName}
+		toGroups, err := manager.GetGroups(ctx, v.Import)
+		if err != nil {
+			return nil, err
+		}
+		toNames := make([]string, 0, len(toGroups))
+		for _, to := range toGroups {
+			toNames = append(toNames, to.Name())
+		}
+		// A private group denies the edges from the importer's groups into itself.
+		if v.Kind == checker.KindPrivate {
+			fromGroups, err := manager.GetGroups(ctx, v.Package)
+			if err != nil {
+				return nil, err
+			}
+			fromNames = fromNames[:0]
+			for _, from := range fromGroups {
+				fromNames = append(fromNames, from.Name())
+			}
+			toNames = []string{v.GroupName}
+		}
+
+		for _, from := range fromNames {
+			for _, to := range toNames {
+				m.Violating[from][to]++
+				if explanation := v.Explanation(); explanation != "" && !slices.Contains(m.Explanations[from][to], explanation) {
+					m.Explanations[from][to] = append(m.Explanations[from][to], explanation)
+				}
+			}
+		}
+	}
+
+	sort.Strings(m.Unassigned)
+	for _, pkgs := range m.GroupPackages {
+		sort.Strings(pkgs)
+	}
+	for _, imports := range m.Imports {
+		sort.Strings(imports)
+	}
+	for _, row := range m.Explanations {
+		for _, explanations := range row {
+			sort.Strings(explanations)
+		}
+	}
+	m.Violations = groupViolations(result.Violations)
+
+	return m, nil
+}
+
+// Matrix returns the edge counts as rows ordered like Groups.
+func (m *Model) Matrix() []MatrixRow {
+	rows := make([]MatrixRow, 0, len(m.Groups))
+	for _, from := range m.Groups {
+		row := MatrixRow{Group: from}
+		for _, to := range m.Groups {
+			row.Cells = append(row.Cells, m.Edges[from][to])
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// groupViolations groups violations by what denied them, so violations without a group are
+// kept apart by kind.
+func groupViolations(violations []checker.Violation) []GroupViolations {
+	byGroup := make(map[string]map[string][]checker.Violation)
+	for _, v := range violations {
+		deniedBy := v.DeniedBy()
+		if _, exists := byGroup[deniedBy]; !exists {
+			byGroup[deniedBy] = make(map[string][]checker.Violation)
+		}
+		byGroup[deniedBy][v.Rule] = append(byGroup[deniedBy][v.Rule], v)
+	}
+
+	var result []GroupViolations
+	for _, deniedBy := range sortedKeys(byGroup) {
+		var gv GroupViolations
+		for _, rule := range sortedKeys(byGroup[deniedBy]) {
+			vs := byGroup[deniedBy][rule]
+			gv.Group = vs[0].GroupName
+			gv.Source = vs[0].Source
+			switch {
+			case gv.Group != "":
+			case vs[0].Kind == checker.KindAlias:
+				gv.Title = "Aliases"
+			default:
+				gv.Title = "Packages without a group"
+			}
+			sort.Slice(vs, func(i, j int) bool {
+				if vs[i].Package != vs[j].Package {
+					return vs[i].Package < vs[j].Package
+				}
+				return vs[i].Import < vs[j].Import
+			})
+			gv.Rules = append(gv.Rules, RuleViolations{Rule: rule, Violations: vs})
+		}
+		result = append(result, gv)
+	}
+	return result
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
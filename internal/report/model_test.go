@@ -0,0 +1,371 @@
+package report
+
+import (
+	"context"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/coderhyme/arch-lint/internal/checker"
+	"github.com/coderhyme/arch-lint/internal/config"
+	"github.com/coderhyme/arch-lint/internal/groups"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ModelSuite struct {
+	suite.Suite
+}
+
+func TestModelSuite(t *testing.T) {
+	suite.Run(t, new(ModelSuite))
+}
+
+func (s *ModelSuite) TestBuild_CountsEdgesAndGroupsViolations() {
+	// given
+	cfg := &config.Config{
+		Version: 1,
+		Groups: map[string]*config.Group{
+			"domain": {
+				Paths: config.PathConfigs{{Dir: "internal/domain/**"}},
+			},
+			"api": {
+				Paths: config.PathConfigs{{Dir: "internal/api/**"}},
+				Dependencies: &config.Dependencies{
+					Deny: &config.DependencyRule{
+						Groups: []string{"domain"},
+					},
+				},
+			},
+		},
+	}
+	manager, err := groups.NewGroupManager(cfg)
+	s.Require().NoError(err)
+
+	packages := map[string]map[string]struct{}{
+		"internal/api/user": {
+			"github.com/example/app/internal/domain/user":  {},
+			"github.com/example/app/internal/domain/order": {},
+			"fmt": {},
+		},
+		"internal/domain/user": {},
+		"internal/tools/gen":   {},
+	}
+	ctx := context.Background()
+	result, err := checker.Check(ctx, "github.com/example/app", packages, manager)
+	s.Require().NoError(err)
+
+	// when
+	model, err := Build(ctx, "github.com/example/app", packages, result, manager)
+
+	// then
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), []string{"api", "domain"}, model.Groups)
+	assert.Equal(s.T(), 2, model.Edges["api"]["domain"])
+	assert.Equal(s.T(), []MatrixRow{
+		{Group: "api", Cells: []int{0, 2}},
+		{Group: "domain", Cells: []int{0, 0}},
+	}, model.Matrix())
+	assert.Equal(s.T(), []string{"internal/tools/gen"}, model.Unassigned)
+	assert.Equal(s.T(), []string{"internal/domain/user"}, model.GroupPackages["domain"])
+	s.Require().Len(model.Violations, 1)
+	assert.Equal(s.T(), "api", model.Violations[0].Group)
+	s.Require().Len(model.Violations[0].Rules, 1)
+	assert.Equal(s.T(), `deny group "domain"`, model.Violations[0].Rules[0].Rule)
+	assert.Len(s.T(), model.Violations[0].Rules[0].Violations, 2)
+}
+
+func (s *ModelSuite) TestNewDSM_ClassifiesCells() {
+	// given
+	cfg := &config.Config{
+		Version: 1,
+		Groups: map[string]*config.Group{
+			"domain": {
+				Paths: config.PathConfigs{{Dir: "internal/domain/**"}},
+				Dependencies: &config.Dependencies{
+					Deny: &config.DependencyRule{
+						Groups: []string{"api"},
+					},
+				},
+			},
+			"api": {
+				Paths: config.PathConfigs{{Dir: "internal/api/**"}},
+				Dependencies: &config.Dependencies{
+					Allow: &config.DependencyRule{
+						Groups: []string{"domain"},
+					},
+				},
+			},
+			"repository": {
+				Paths: config.PathConfigs{{Dir: "internal/repository/**"}},
+			},
+		},
+	}
+	manager, err := groups.NewGroupManager(cfg)
+	s.Require().NoError(err)
+
+	packages := map[string]map[string]struct{}{
+		"internal/api/user": {
+			"github.com/example/app/internal/domain/user":    {},
+			"github.com/example/app/internal/repository/sql": {},
+		},
+		"internal/domain/user":    {},
+		"internal/repository/sql": {},
+	}
+	ctx := context.Background()
+	result, err := checker.Check(ctx, "github.com/example/app", packages, manager)
+	s.Require().NoError(err)
+	model, err := Build(ctx, "github.com/example/app", packages, result, manager)
+	s.Require().NoError(err)
+
+	// when
+	dsm, err := NewDSM(ctx, model, manager)
+
+	// then - groups are ordered api, domain, repository
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), DSMCell{Count: 1, Status: CellAllowed}, dsm.Cells[0][1])
+	assert.Equal(s.T(), DSMCell{Count: 1, Status: CellViolation}, dsm.Cells[0][2])
+	assert.Equal(s.T(), DSMCell{Count: 0, Status: CellDenied}, dsm.Cells[1][0])
+	assert.Equal(s.T(), DSMCell{Count: 0, Status: CellAllowed}, dsm.Cells[2][0])
+	assert.Equal(s.T(), DSMCell{Count: 0, Status: CellUnknown}, dsm.Cells[1][1])
+}
+
+func (s *ModelSuite) TestWriteDSM_ListsMessagesOfViolatingCells() {
+	// given
+	cfg := &config.Config{
+		Version: 1,
+		Groups: map[string]*config.Group{
+			"api": {
+				Paths: config.PathConfigs{{Dir: "internal/api/**"}},
+				Dependencies: &config.Dependencies{
+					Deny: &config.DependencyRule{
+						Groups:  []string{"repository"},
+						Message: "go through the service layer",
+						Docs:    "https://example.com/layers",
+					},
+				},
+			},
+			"repository": {
+				Paths: config.PathConfigs{{Dir: "internal/repository/**"}},
+			},
+		},
+	}
+	manager, err := groups.NewGroupManager(cfg)
+	s.Require().NoError(err)
+
+	packages := map[string]map[string]struct{}{
+		"internal/api/user": {
+			"github.com/example/app/internal/repository/sql": {},
+			"github.com/example/app/internal/repository/mem": {},
+		},
+		"internal/repository/sql": {},
+		"internal/repository/mem": {},
+	}
+	ctx := context.Background()
+	result, err := checker.Check(ctx, "github.com/example/app", packages, manager)
+	s.Require().NoError(err)
+	model, err := Build(ctx, "github.com/example/app", packages, result, manager)
+	s.Require().NoError(err)
+	dsm, err := NewDSM(ctx, model, manager)
+	s.Require().NoError(err)
+
+	// when
+	var text, csv strings.Builder
+	textErr := WriteDSMText(&text, dsm)
+	csvErr := WriteDSMCSV(&csv, dsm)
+
+	// then - the message of both violating edges is listed once
+	s.Require().NoError(textErr)
+	s.Require().NoError(csvErr)
+	assert.Equal(s.T(), []string{"go through the service layer (see https://example.com/layers)"}, dsm.Cells[0][1].Notes)
+	assert.Contains(s.T(), text.String(), "\nViolated rules:\n  api -> repository: go through the service layer (see https://example.com/layers)\n")
+	assert.Equal(s.T(), "group,api,repository,notes\n"+
+		"api,0:unknown,2:violation,repository: go through the service layer (see https://example.com/layers)\n"+
+		"repository,0:allowed,0:allowed,\n", csv.String())
+}
+
+func (s *ModelSuite) TestNewDSM_PrivateGroupViolationsOnImporterRow() {
+	// given
+	cfg := &config.Config{
+		Version: 1,
+		Groups: map[string]*config.Group{
+			"api": {
+				Paths: config.PathConfigs{{Dir: "internal/api"}, {Dir: "internal/api/**"}},
+			},
+			"billing": {
+				Paths:   config.PathConfigs{{Dir: "internal/billing/**"}},
+				Private: true,
+			},
+			"web": {
+				Paths: config.PathConfigs{{Dir: "internal/web/**"}},
+			},
+		},
+	}
+	manager, err := groups.NewGroupManager(cfg)
+	s.Require().NoError(err)
+
+	packages := map[string]map[string]struct{}{
+		"internal/api": {
+			"github.com/example/app/internal/billing/invoice": {},
+		},
+		"internal/billing/invoice": {},
+		"internal/web/page":        {},
+	}
+	ctx := context.Background()
+	result, err := checker.Check(ctx, "github.com/example/app", packages, manager)
+	s.Require().NoError(err)
+	private, err := checker.CheckPrivate(ctx, "github.com/example/app", packages, manager)
+	s.Require().NoError(err)
+	result.Violations = append(result.Violations, private...)
+
+	// when
+	model, err := Build(ctx, "github.com/example/app", packages, result, manager)
+	s.Require().NoError(err)
+	dsm, err := NewDSM(ctx, model, manager)
+
+	// then - groups are ordered api, billing, web
+	s.Require().NoError(err)
+	assert.Equal(s.T(), 1, model.Violating["api"]["billing"])
+	assert.Zero(s.T(), model.Violating["billing"]["billing"])
+	assert.Equal(s.T(), DSMCell{Count: 1, Status: CellViolation}, dsm.Cells[0][1])
+	assert.Equal(s.T(), CellUnknown, dsm.Cells[1][1].Status)
+	assert.Equal(s.T(), DSMCell{Count: 0, Status: CellDenied}, dsm.Cells[2][1])
+	assert.Equal(s.T(), DSMCell{Count: 0, Status: CellAllowed}, dsm.Cells[1][2])
+}
+
+func (s *ModelSuite) TestCompare_ReportsAddedEdgesAndNewViolations() {
+	// given
+	cfg := &config.Config{
+		Version: 1,
+		Groups: map[string]*config.Group{
+			"api": {
+				Paths: config.PathConfigs{{Dir: "internal/api/**"}},
+				Dependencies: &config.Dependencies{
+					Deny: &config.DependencyRule{
+						Groups: []string{"repository"},
+					},
+					Allow: &config.DependencyRule{
+						Patterns: []string{"internal/**"},
+					},
+				},
+			},
+			"repository": {
+				Paths: config.PathConfigs{{Dir: "internal/repository/**"}},
+			},
+		},
+	}
+	manager, err := groups.NewGroupManager(cfg)
+	s.Require().NoError(err)
+
+	ctx := context.Background()
+	build := func(packages map[string]map[string]struct{}) *Model {
+		result, err := checker.Check(ctx, "github.com/example/app", packages, manager)
+		s.Require().NoError(err)
+		model, err := Build(ctx, "github.com/example/app", packages, result, manager)
+		s.Require().NoError(err)
+		return model
+	}
+	base := build(map[string]map[string]struct{}{
+		"internal/api/user": {
+			"github.com/example/app/internal/util/log": {},
+		},
+		"internal/repository/sql": {},
+	})
+	head := build(map[string]map[string]struct{}{
+		"internal/api/user": {
+			"github.com/example/app/internal/repository/sql": {},
+		},
+		"internal/repository/sql": {},
+	})
+
+	// when
+	diff := Compare(base, head)
+
+	// then
+	assert.Equal(s.T(), []GroupEdge{{From: "api", To: "repository", Count: 1}}, diff.AddedGroupEdges)
+	assert.Equal(s.T(), []PackageEdge{{From: "internal/api/user", To: "internal/repository/sql"}}, diff.AddedPackageEdges)
+	assert.Equal(s.T(), []PackageEdge{{From: "internal/api/user", To: "internal/util/log"}}, diff.RemovedPackageEdges)
+	s.Require().Len(diff.NewViolations, 1)
+	assert.Equal(s.T(), "internal/repository/sql", diff.NewViolations[0].Import)
+	assert.Empty(s.T(), diff.AddedGroups)
+}
+
+func (s *ModelSuite) TestCompare_LimitWithChangedCountIsNotNew() {
+	// given
+	cfg := &config.Config{
+		Version: 1,
+		Groups: map[string]*config.Group{
+			"api": {
+				Paths:      config.PathConfigs{{Dir: "internal/api/**"}},
+				MaxImports: 1,
+			},
+		},
+	}
+	manager, err := groups.NewGroupManager(cfg)
+	s.Require().NoError(err)
+
+	ctx := context.Background()
+	build := func(packages map[string]map[string]struct{}) *Model {
+		violations, err := checker.CheckLimits(ctx, "github.com/example/app", packages, packages, manager)
+		s.Require().NoError(err)
+		model, err := Build(ctx, "github.com/example/app", packages, &checker.Result{Violations: violations}, manager)
+		s.Require().NoError(err)
+		return model
+	}
+	base := build(map[string]map[string]struct{}{
+		"internal/api/user": {
+			"github.com/example/app/internal/util/log":  {},
+			"github.com/example/app/internal/util/time": {},
+		},
+	})
+	head := build(map[string]map[string]struct{}{
+		"internal/api/user": {
+			"github.com/example/app/internal/util/log":  {},
+			"github.com/example/app/internal/util/time": {},
+		},
+		"internal/api/order": {
+			"github.com/example/app/internal/util/sql": {},
+			"github.com/example/app/internal/util/db":  {},
+			"github.com/example/app/internal/util/ids": {},
+		},
+	})
+
+	// when
+	diff := Compare(base, head)
+
+	// then - the top offender and count change, but the group is still over the same limit
+	s.Require().Len(base.Violations, 1)
+	s.Require().Len(head.Violations, 1)
+	assert.Empty(s.T(), diff.NewViolations)
+	assert.Empty(s.T(), diff.ResolvedViolations)
+}
+
+func (s *ModelSuite) TestWriteHTML_ShowsPositionsOfViolations() {
+	// given
+	cfg := &config.Config{
+		Version: 1,
+		Groups: map[string]*config.Group{
+			"api": {Paths: config.PathConfigs{{Dir: "internal/api/**"}}},
+		},
+	}
+	manager, err := groups.NewGroupManager(cfg)
+	s.Require().NoError(err)
+
+	result := &checker.Result{Violations: []checker.Violation{
+		{Kind: checker.KindCall, Package: "internal/api/user", GroupName: "api", Rule: "call os.Exit forbidden", Call: "os.Exit",
+			Pos: token.Position{Filename: "internal/api/user/user.go", Line: 12, Column: 2}},
+		{Kind: checker.KindImport, Package: "internal/api/user", Import: "internal/legacy", GroupName: "api", Rule: "not allowed"},
+	}}
+	ctx := context.Background()
+	model, err := Build(ctx, "github.com/example/app", map[string]map[string]struct{}{"internal/api/user": {}}, result, manager)
+	s.Require().NoError(err)
+
+	// when
+	var out strings.Builder
+	err = WriteHTML(&out, model)
+
+	// then
+	s.Require().NoError(err)
+	assert.Contains(s.T(), out.String(), "<code>os.Exit</code> <small>internal/api/user/user.go:12:2</small>")
+	assert.Contains(s.T(), out.String(), "<code>internal/legacy</code></td>")
+}
@@ -0,0 +1,76 @@
+package vcs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ChangedFiles lists the files under dir that differ from rev in the working tree, including
+// untracked files. Paths are slash-separated and relative to dir.
+func ChangedFiles(ctx context.Context, dir, rev string) ([]string, error) {
+	diff, err := git(ctx, dir, "diff", "--name-only", "--relative", "--no-renames", rev, "--")
+	if err != nil {
+		return nil, err
+	}
+
+	untracked, err := git(ctx, dir, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(lines(diff), lines(untracked)...), nil
+}
+
+func git(ctx context.Context, dir string, args ...string) (string, error) {
+	out, err := gitBytes(ctx, dir, args...)
+	return string(out), err
+}
+
+func gitBytes(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+func lines(output string) []string {
+	var result []string
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			result = append(result, line)
+		}
+	}
+	return result
+}
+
+// Export writes the contents of dir at rev into dest, which must exist. Only the subtree
+// rooted at dir is exported, so dest corresponds to dir.
+func Export(ctx context.Context, dir, rev, dest string) error {
+	prefix, err := git(ctx, dir, "rev-parse", "--show-prefix")
+	if err != nil {
+		return err
+	}
+	top, err := git(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return err
+	}
+
+	// git archive limits its output to the working directory, which the tree of the
+	// subtree does not contain, so it runs from the top of the repository.
+	archive, err := gitBytes(ctx, strings.TrimSpace(top), "archive", "--format=tar", rev+":"+strings.TrimSpace(prefix))
+	if err != nil {
+		return err
+	}
+
+	return untar(bytes.NewReader(archive), dest)
+}
@@ -0,0 +1,114 @@
+package vcs
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type GitSuite struct {
+	suite.Suite
+	root string
+}
+
+func TestGitSuite(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	suite.Run(t, new(GitSuite))
+}
+
+func (s *GitSuite) SetupTest() {
+	s.root = s.T().TempDir()
+	s.git("init", "-q")
+	s.git("config", "user.email", "test@example.com")
+	s.git("config", "user.name", "test")
+	s.git("config", "commit.gpgsign", "false")
+}
+
+func (s *GitSuite) git(args ...string) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = s.root
+	out, err := cmd.CombinedOutput()
+	s.Require().NoError(err, string(out))
+}
+
+func (s *GitSuite) write(name, content string) {
+	path := filepath.Join(s.root, name)
+	s.Require().NoError(os.MkdirAll(filepath.Dir(path), 0o755))
+	s.Require().NoError(os.WriteFile(path, []byte(content), 0o644))
+}
+
+func (s *GitSuite) commit(message string) {
+	s.git("add", "-A")
+	s.git("commit", "-q", "-m", message)
+}
+
+func (s *GitSuite) TestChangedFiles_ModifiedAndUntracked() {
+	// given
+	s.write("a/a.go", "package a\n")
+	s.write("b/b.go", "package b\n")
+	s.write(".gitignore", "ignored.go\n")
+	s.commit("initial")
+	s.write("a/a.go", "package a\n\nimport \"fmt\"\n")
+	s.write("c/c.go", "package c\n")
+	s.write("ignored.go", "package main\n")
+
+	// when
+	files, err := ChangedFiles(context.Background(), s.root, "HEAD")
+
+	// then
+	s.Require().NoError(err)
+	assert.ElementsMatch(s.T(), []string{"a/a.go", "c/c.go"}, files)
+}
+
+func (s *GitSuite) TestChangedFiles_RelativeToSubdirectory() {
+	// given
+	s.write("other/x.go", "package x\n")
+	s.write("mod/a/a.go", "package a\n")
+	s.commit("initial")
+	s.write("other/x.go", "package x\n\nimport \"fmt\"\n")
+	s.write("mod/a/a.go", "package a\n\nimport \"fmt\"\n")
+	s.write("mod/b/b.go", "package b\n")
+	s.write("other/y.go", "package x\n")
+
+	// when
+	files, err := ChangedFiles(context.Background(), filepath.Join(s.root, "mod"), "HEAD")
+
+	// then - paths are relative to the subdirectory and changes outside it are left out
+	s.Require().NoError(err)
+	assert.ElementsMatch(s.T(), []string{"a/a.go", "b/b.go"}, files)
+}
+
+func (s *GitSuite) TestChangedFiles_SinceEarlierRevision() {
+	// given
+	s.write("a/a.go", "package a\n")
+	s.commit("initial")
+	s.git("tag", "base")
+	s.write("b/b.go", "package b\n")
+	s.commit("second")
+
+	// when
+	files, err := ChangedFiles(context.Background(), s.root, "base")
+
+	// then
+	s.Require().NoError(err)
+	assert.Equal(s.T(), []string{"b/b.go"}, files)
+}
+
+func (s *GitSuite) TestChangedFiles_UnknownRevision() {
+	// given
+	s.write("a/a.go", "package a\n")
+	s.commit("initial")
+
+	// when
+	_, err := ChangedFiles(context.Background(), s.root, "no-such-rev")
+
+	// then
+	assert.Error(s.T(), err)
+}
@@ -0,0 +1,112 @@
+package vcs
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *GitSuite) TestExport_WritesRevisionContents() {
+	// given
+	s.write("go.mod", "module example.com/app\n")
+	s.write("internal/a/a.go", "package a\n")
+	s.commit("initial")
+	s.git("tag", "base")
+	s.write("internal/a/a.go", "package a\n\nimport \"fmt\"\n")
+	s.write("internal/b/b.go", "package b\n")
+	dest := s.T().TempDir()
+
+	// when
+	err := Export(context.Background(), s.root, "base", dest)
+
+	// then - the tree of the revision, not the working tree
+	s.Require().NoError(err)
+	content, err := os.ReadFile(filepath.Join(dest, "internal", "a", "a.go"))
+	s.Require().NoError(err)
+	assert.Equal(s.T(), "package a\n", string(content))
+	assert.FileExists(s.T(), filepath.Join(dest, "go.mod"))
+	assert.NoFileExists(s.T(), filepath.Join(dest, "internal", "b", "b.go"))
+}
+
+func (s *GitSuite) TestExport_OnlySubtreeOfDirectory() {
+	// given
+	s.write("README.md", "docs\n")
+	s.write("mod/go.mod", "module example.com/app\n")
+	s.write("mod/a/a.go", "package a\n")
+	s.commit("initial")
+	dest := s.T().TempDir()
+
+	// when
+	err := Export(context.Background(), filepath.Join(s.root, "mod"), "HEAD", dest)
+
+	// then
+	s.Require().NoError(err)
+	assert.FileExists(s.T(), filepath.Join(dest, "go.mod"))
+	assert.FileExists(s.T(), filepath.Join(dest, "a", "a.go"))
+	assert.NoFileExists(s.T(), filepath.Join(dest, "README.md"))
+}
+
+func (s *GitSuite) TestExport_UnknownRevision() {
+	// given
+	s.write("a.go", "package a\n")
+	s.commit("initial")
+
+	// when
+	err := Export(context.Background(), s.root, "no-such-rev", s.T().TempDir())
+
+	// then
+	assert.Error(s.T(), err)
+}
+
+func (s *GitSuite) TestUntar_RejectsEntriesOutsideDestination() {
+	// given
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	content := []byte("package evil\n")
+	s.Require().NoError(tw.WriteHeader(&tar.Header{Name: "../evil.go", Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(content))}))
+	_, err := tw.Write(content)
+	s.Require().NoError(err)
+	s.Require().NoError(tw.Close())
+	dest := filepath.Join(s.T().TempDir(), "dest")
+	s.Require().NoError(os.Mkdir(dest, 0o755))
+
+	// when
+	err = untar(&buf, dest)
+
+	// then
+	assert.ErrorContains(s.T(), err, "escapes destination")
+	assert.NoFileExists(s.T(), filepath.Join(filepath.Dir(dest), "evil.go"))
+}
+
+func (s *GitSuite) TestUntar_CreatesDirectoriesAndFiles() {
+	// given
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	content := []byte("package a\n")
+	s.Require().NoError(tw.WriteHeader(&tar.Header{Name: "empty/", Typeflag: tar.TypeDir, Mode: 0o755}))
+	s.Require().NoError(tw.WriteHeader(&tar.Header{Name: "nested/a/a.go", Typeflag: tar.TypeReg, Mode: 0o600, Size: int64(len(content))}))
+	_, err := tw.Write(content)
+	s.Require().NoError(err)
+	s.Require().NoError(tw.WriteHeader(&tar.Header{Name: "link.go", Typeflag: tar.TypeSymlink, Linkname: "/etc/passwd"}))
+	s.Require().NoError(tw.Close())
+	dest := s.T().TempDir()
+
+	// when
+	err = untar(&buf, dest)
+
+	// then - symlinks are skipped
+	s.Require().NoError(err)
+	assert.DirExists(s.T(), filepath.Join(dest, "empty"))
+	written, err := os.ReadFile(filepath.Join(dest, "nested", "a", "a.go"))
+	s.Require().NoError(err)
+	assert.Equal(s.T(), content, written)
+	info, err := os.Stat(filepath.Join(dest, "nested", "a", "a.go"))
+	s.Require().NoError(err)
+	assert.Equal(s.T(), os.FileMode(0o600), info.Mode().Perm())
+	_, err = os.Lstat(filepath.Join(dest, "link.go"))
+	assert.True(s.T(), os.IsNotExist(err))
+}